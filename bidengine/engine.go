@@ -0,0 +1,304 @@
+// Package bidengine bids for the open deployment orders fitting the
+// inventory of the datacenter, and follows the bids until the orders close
+package bidengine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// bidRetention is how long the closed bids are listed
+const bidRetention = 24 * time.Hour
+
+// wonHold is how long a won bid holds its resources at most, waiting the task
+// of the order reserved
+const wonHold = 10 * time.Minute
+
+// State of a bid
+type State string
+
+const (
+	// Submitted bids wait the order to close, their resources are held
+	Submitted State = "submitted"
+	// Won bids hold their resources until the task of the order is reserved
+	Won  State = "won"
+	Lost State = "lost"
+	// Closed is an order closed with the deployment
+	Closed State = "closed"
+)
+
+// Bid is the offer of the provider for an order
+type Bid struct {
+	Order     types.DeploymentGroupID `json:"order"`
+	Price     uint64                  `json:"price"`
+	State     State                   `json:"state"`
+	Resources []types.ResourceGroup   `json:"resources"`
+	Created   time.Time               `json:"created"`
+	Updated   time.Time               `json:"updated"`
+	// Reservation of the task of the won order
+	Reserved string `json:"reserved,omitempty"`
+}
+
+// holds returns whether the resources of the bid are held, the submitted bids
+// may win and the won ones wait their task reserved
+func (b *Bid) holds() bool {
+	return b.State == Submitted || b.State == Won && b.Reserved == "" && time.Since(b.Updated) < wonHold
+}
+
+// Inventory is the capacity of the nodes, implemented by the
+// inventory.Inventory
+type Inventory interface {
+	Available() (map[string]*types.ResourceUnit, error)
+	Attributes() (map[string][]types.ProviderAttribute, error)
+	Reservations() []inventory.Reservation
+}
+
+// Engine bids for the orders whose resource groups fit the available
+// capacity of the nodes with the required attributes. The resources of the
+// submitted bids are held until the orders close, the won orders are then
+// deployed and reserved through the hub, their resources are held until the
+// inventory reserves them.
+type Engine struct {
+	sync.Mutex
+	source    Source
+	inventory Inventory
+	pricing   *Pricing
+	provider  base.Bytes
+
+	bids map[string]*Bid
+}
+
+func New(source Source, inv Inventory, pricing *Pricing, provider base.Bytes) *Engine {
+	return &Engine{
+		source:    source,
+		inventory: inv,
+		pricing:   pricing,
+		provider:  provider,
+		bids:      map[string]*Bid{},
+	}
+}
+
+// Run polls the orders every interval until stop is closed
+func (e *Engine) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Poll(); err != nil {
+			glog.Errorln("bid engine:", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll follows the submitted bids and the won ones until reserved, then bids
+// for the new open orders
+func (e *Engine) Poll() error {
+	e.Lock()
+	defer e.Unlock()
+
+	e.follow()
+	e.reserved()
+	orders, err := e.source.Orders()
+	if err != nil {
+		return errors.Wrap(err, "list orders")
+	}
+	for _, order := range orders {
+		if _, ok := e.bids[order.Group.DeploymentGroupID.String()]; ok {
+			continue
+		}
+		if err := e.bid(order); err != nil {
+			glog.V(1).Infof("no bid for order %s: %v", order.Group.DeploymentGroupID, err)
+		}
+	}
+	return nil
+}
+
+// Bids returns the bids by order
+func (e *Engine) Bids() []Bid {
+	e.Lock()
+	defer e.Unlock()
+
+	bids := make([]Bid, 0, len(e.bids))
+	for _, bid := range e.bids {
+		bids = append(bids, *bid)
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Order.Compare(bids[j].Order) < 0 })
+	return bids
+}
+
+// Orders counts the open orders bid for
+func (e *Engine) Orders() int {
+	e.Lock()
+	defer e.Unlock()
+
+	count := 0
+	for _, bid := range e.bids {
+		if bid.State == Submitted {
+			count++
+		}
+	}
+	return count
+}
+
+// bid submits the price of the order if it fits
+func (e *Engine) bid(order *chain.Order) error {
+	available, err := e.inventory.Available()
+	if err != nil {
+		return err
+	}
+	attributes, err := e.inventory.Attributes()
+	if err != nil {
+		return err
+	}
+
+	// the submitted bids may win, the won ones are not reserved yet
+	for _, bid := range e.bids {
+		if bid.holds() {
+			place(available, attributes, nil, bid.Resources)
+		}
+	}
+	if err := place(available, attributes, order.Group.Requirements, order.Group.Resources); err != nil {
+		return err
+	}
+	price, err := e.pricing.Price(order.Group.Resources)
+	if err != nil {
+		return err
+	}
+
+	id := order.Group.DeploymentGroupID
+	if err := e.source.Bid(id, price); err != nil {
+		return errors.Wrap(err, "submit bid")
+	}
+	glog.Infof("bid %d for order %s", price, id)
+
+	now := time.Now()
+	e.bids[id.String()] = &Bid{
+		Order:     id,
+		Price:     price,
+		State:     Submitted,
+		Resources: order.Group.Resources,
+		Created:   now,
+		Updated:   now,
+	}
+	return nil
+}
+
+// follow updates the submitted bids with the state of their orders, and
+// drops the old closed bids
+func (e *Engine) follow() {
+	for key, bid := range e.bids {
+		if bid.State != Submitted {
+			if time.Since(bid.Updated) > bidRetention {
+				delete(e.bids, key)
+			}
+			continue
+		}
+
+		order, err := e.source.Order(bid.Order)
+		if err != nil {
+			glog.Errorf("follow bid for order %s: %v", bid.Order, err)
+			continue
+		}
+		switch order.Group.State {
+		case types.DeploymentGroup_OPEN:
+			continue
+		case types.DeploymentGroup_ORDERED:
+			if order.Winner.Equal(e.provider) {
+				bid.State = Won
+			} else {
+				bid.State = Lost
+			}
+		default:
+			bid.State = Closed
+		}
+		bid.Updated = time.Now()
+		glog.Infof("bid for order %s %s", bid.Order, bid.State)
+	}
+}
+
+// reserved matches the won bids holding their resources with the
+// reservations of the tasks of their orders
+func (e *Engine) reserved() {
+	reservations := map[string]string{}
+	for _, reservation := range e.inventory.Reservations() {
+		if reservation.Order != "" {
+			reservations[reservation.Order] = reservation.Name
+		}
+	}
+
+	for _, bid := range e.bids {
+		if bid.State != Won || !bid.holds() {
+			continue
+		}
+		if name, ok := reservations[bid.Order.String()]; ok {
+			bid.Reserved = name
+			glog.Infof("bid for order %s reserved by %s", bid.Order, name)
+		}
+	}
+}
+
+// place fits the resource groups on the nodes with the required attributes,
+// first fit over the sorted nodes, and subtracts them from the available
+// capacity
+func place(available map[string]*types.ResourceUnit, attributes map[string][]types.ProviderAttribute,
+	requirements []types.ProviderAttribute, groups []types.ResourceGroup) error {
+	var nodes []string
+	for node := range available {
+		if matches(attributes[node], requirements) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+
+	for i := range groups {
+		unit := &groups[i].Unit
+		for count := uint32(0); count < groups[i].Count; count++ {
+			node := ""
+			for _, candidate := range nodes {
+				if available[candidate].Fits(unit) {
+					node = candidate
+					break
+				}
+			}
+			if node == "" {
+				return errors.Errorf("%d units of group %d do not fit in the available capacity, %d placed",
+					groups[i].Count, i, count)
+			}
+			m := available[node]
+			m.CPU -= unit.CPU
+			m.Memory -= unit.Memory
+			m.Disk -= unit.Disk
+		}
+	}
+	return nil
+}
+
+// matches returns whether the attributes have all the requirements
+func matches(attributes, requirements []types.ProviderAttribute) bool {
+	for _, requirement := range requirements {
+		found := false
+		for _, attribute := range attributes {
+			if attribute.Name == requirement.Name && attribute.Value == requirement.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
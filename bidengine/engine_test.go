@@ -0,0 +1,133 @@
+package bidengine_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/bidengine"
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInventory struct {
+	reservations []inventory.Reservation
+}
+
+func (*fakeInventory) Available() (map[string]*types.ResourceUnit, error) {
+	return map[string]*types.ResourceUnit{
+		"a": {CPU: 1000, Memory: 1 << 30},
+		"b": {CPU: 2000, Memory: 1 << 30},
+	}, nil
+}
+
+func (*fakeInventory) Attributes() (map[string][]types.ProviderAttribute, error) {
+	return map[string][]types.ProviderAttribute{
+		"b": {{Name: "region", Value: "us"}},
+	}, nil
+}
+
+func (inv *fakeInventory) Reservations() []inventory.Reservation {
+	return inv.reservations
+}
+
+func order(seq uint64, cpu uint32, count uint32, price uint64, requirements ...types.ProviderAttribute) *chain.Order {
+	return &chain.Order{Group: types.DeploymentGroup{
+		DeploymentGroupID: types.DeploymentGroupID{Deployment: base.Bytes{1}, Seq: seq},
+		OrderTTL:          10,
+		Requirements:      requirements,
+		Resources: []types.ResourceGroup{
+			{Unit: types.ResourceUnit{CPU: cpu, Memory: 1 << 28}, Count: count, Price: price},
+		},
+	}}
+}
+
+func TestPricing_Price(t *testing.T) {
+	pricing := &bidengine.Pricing{CPU: 100, Memory: 40, Minimum: 5, Margin: 50}
+	price, err := pricing.Price([]types.ResourceGroup{{Unit: types.ResourceUnit{CPU: 500, Memory: 1 << 30}, Count: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2*135), price)
+	assert.Equal(t, uint64(5), pricing.UnitPrice(&types.ResourceUnit{CPU: 10}))
+
+	_, err = pricing.Price([]types.ResourceGroup{{Unit: types.ResourceUnit{CPU: 500}, Count: 1, Price: 10}})
+	assert.Error(t, err)
+}
+
+func TestEngine_Poll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bidengine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "orders.json")
+	save := func(orders ...*chain.Order) {
+		data, err := json.Marshal(orders)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(path, data, 0644))
+	}
+	provider := base.Bytes{9}
+
+	fits := order(1, 1000, 2, 0)
+	regional := order(2, 1500, 1, 0, types.ProviderAttribute{Name: "region", Value: "us"})
+	cheap := order(3, 200, 1, 1)
+	save(fits, regional, cheap)
+
+	source := bidengine.NewFileSource(path, provider)
+	inv := &fakeInventory{}
+	engine := bidengine.New(source, inv, &bidengine.Pricing{CPU: 10}, provider)
+	require.NoError(t, engine.Poll())
+
+	// the regional order does not fit on b after the first order, and the
+	// cheap order is priced under the policy
+	bids := engine.Bids()
+	require.Len(t, bids, 1)
+	assert.Equal(t, uint64(20), bids[0].Price)
+	assert.Equal(t, 1, engine.Orders())
+
+	fits, err = source.Order(fits.Group.DeploymentGroupID)
+	require.NoError(t, err)
+	require.Len(t, fits.Bids, 1)
+	assert.Equal(t, provider, fits.Bids[0].Provider)
+
+	// the won order holds its resources until its task is reserved
+	fits.Group.State, fits.Winner = types.DeploymentGroup_ORDERED, provider
+	save(fits, regional, cheap)
+	require.NoError(t, engine.Poll())
+
+	bids = engine.Bids()
+	require.Len(t, bids, 1)
+	assert.Equal(t, bidengine.Won, bids[0].State)
+
+	// a task of the same unit and count without the order is not its task
+	inv.reservations = []inventory.Reservation{{
+		Name:    "api",
+		Unit:    fits.Group.Resources[0].Unit,
+		Nodes:   []string{"a", "b"},
+		Created: time.Now(),
+	}}
+	require.NoError(t, engine.Poll())
+	bids = engine.Bids()
+	require.Len(t, bids, 1)
+	assert.Empty(t, bids[0].Reserved)
+
+	inv.reservations = append(inv.reservations, inventory.Reservation{
+		Name:    "web",
+		Order:   fits.Group.DeploymentGroupID.String(),
+		Unit:    fits.Group.Resources[0].Unit,
+		Nodes:   []string{"a", "b"},
+		Created: time.Now(),
+	})
+	require.NoError(t, engine.Poll())
+
+	bids = engine.Bids()
+	require.Len(t, bids, 2)
+	assert.Equal(t, "web", bids[0].Reserved)
+	assert.Equal(t, bidengine.Submitted, bids[1].State)
+	assert.Equal(t, uint64(15), bids[1].Price)
+}
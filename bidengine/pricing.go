@@ -0,0 +1,73 @@
+package bidengine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+)
+
+const gib = 1 << 30
+
+// Pricing is the policy pricing the bids per unit hour, like the resource
+// groups of the orders
+type Pricing struct {
+	// Per core, and per GiB of memory and of disk
+	CPU    uint64 `json:"cpu"`
+	Memory uint64 `json:"memory"`
+	Disk   uint64 `json:"disk"`
+	// Lowest price of a unit
+	Minimum uint64 `json:"minimum"`
+	// Percentage over the price of the resources
+	Margin uint64 `json:"margin"`
+}
+
+// DefaultPricing is used without a pricing file
+var DefaultPricing = Pricing{CPU: 100, Memory: 50, Disk: 5, Minimum: 1}
+
+// LoadPricing reads the json pricing file, the default pricing if path is
+// empty
+func LoadPricing(path string) (*Pricing, error) {
+	pricing := DefaultPricing
+	if path == "" {
+		return &pricing, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load pricing")
+	}
+	if err := json.Unmarshal(data, &pricing); err != nil {
+		return nil, errors.Wrap(err, "load pricing")
+	}
+	return &pricing, nil
+}
+
+// UnitPrice is the price of the unit per hour, rounded up
+func (p *Pricing) UnitPrice(unit *types.ResourceUnit) uint64 {
+	price := float64(unit.CPU)*float64(p.CPU)/1000 +
+		float64(unit.Memory)*float64(p.Memory)/gib +
+		float64(unit.Disk)*float64(p.Disk)/gib
+	price *= 1 + float64(p.Margin)/100
+
+	if rounded := uint64(math.Ceil(price)); rounded > p.Minimum {
+		return rounded
+	}
+	return p.Minimum
+}
+
+// Price is the price of the resource groups per hour, refused if the price
+// of a unit is over the price of its group
+func (p *Pricing) Price(groups []types.ResourceGroup) (uint64, error) {
+	var total uint64
+	for i := range groups {
+		price := p.UnitPrice(&groups[i].Unit)
+		if groups[i].Price != 0 && price > groups[i].Price {
+			return 0, errors.Errorf("unit price %d over the price %d of group %d", price, groups[i].Price, i)
+		}
+		total += price * uint64(groups[i].Count)
+	}
+	return total, nil
+}
@@ -0,0 +1,176 @@
+package bidengine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Source is the market of the deployment orders
+type Source interface {
+	// Orders returns the open orders
+	Orders() ([]*chain.Order, error)
+	// Order returns the order of the deployment group, in any state
+	Order(id types.DeploymentGroupID) (*chain.Order, error)
+	// Bid offers the price per hour for the order
+	Bid(id types.DeploymentGroupID, price uint64) error
+}
+
+// Chain is the tendermint rpc of the chain source, implemented by the
+// client.HTTP
+type Chain interface {
+	ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error)
+	BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error)
+}
+
+type chainSource struct {
+	chain  Chain
+	key    ed25519.PrivKeyEd25519
+	dcName string
+}
+
+// NewChainSource queries the orders of the chain application, and bids with
+// txs signed by the provider key
+func NewChainSource(c Chain, key ed25519.PrivKeyEd25519, dcName string) Source {
+	return &chainSource{chain: c, key: key, dcName: dcName}
+}
+
+func (s *chainSource) query(path string, data []byte, result interface{}) error {
+	res, err := s.chain.ABCIQuery(path, data)
+	if err != nil {
+		return errors.Wrap(err, "query "+path)
+	}
+	if res.Response.Code != chain.CodeOK {
+		return errors.Errorf("query %s code %d: %s", path, res.Response.Code, res.Response.Log)
+	}
+	return errors.Wrap(json.Unmarshal(res.Response.Value, result), "query "+path)
+}
+
+func (s *chainSource) Orders() ([]*chain.Order, error) {
+	var orders []*chain.Order
+	return orders, s.query(chain.QueryOrders, nil, &orders)
+}
+
+func (s *chainSource) Order(id types.DeploymentGroupID) (*chain.Order, error) {
+	order := &chain.Order{}
+	return order, s.query(chain.QueryOrder, []byte(id.String()), order)
+}
+
+func (s *chainSource) Bid(id types.DeploymentGroupID, price uint64) error {
+	payload, err := json.Marshal(&chain.TxCreateBid{Group: id, Price: price})
+	if err != nil {
+		return errors.Wrap(err, "json marshal")
+	}
+	envelope := &types.TxEnvelope{
+		Type:       types.TxEnvelope_CREATE_BID,
+		Version:    types.TxVersion,
+		DataCenter: s.dcName,
+		Payload:    payload,
+	}
+	if err := envelope.Sign(s.key); err != nil {
+		return err
+	}
+	tx, err := types.EncodeTx(envelope)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.chain.BroadcastTxSync(tx)
+	if err != nil {
+		return errors.Wrap(err, "broadcast bid")
+	}
+	if res.Code != chain.CodeOK {
+		return errors.Errorf("bid for %s code %d: %s", id, res.Code, res.Log)
+	}
+	return nil
+}
+
+type fileSource struct {
+	sync.Mutex
+	path     string
+	provider base.Bytes
+}
+
+// NewFileSource reads the orders from the json file of []chain.Order, for
+// testing without a chain. The bids of the provider are written into the
+// file, whose orders are closed by hand.
+func NewFileSource(path string, provider base.Bytes) Source {
+	return &fileSource{path: path, provider: provider}
+}
+
+func (s *fileSource) load() ([]*chain.Order, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load orders")
+	}
+	var orders []*chain.Order
+	return orders, errors.Wrap(json.Unmarshal(data, &orders), "load orders")
+}
+
+func (s *fileSource) Orders() ([]*chain.Order, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	orders, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	open := orders[:0]
+	for _, order := range orders {
+		if order.Group.State == types.DeploymentGroup_OPEN {
+			open = append(open, order)
+		}
+	}
+	return open, nil
+}
+
+func (s *fileSource) Order(id types.DeploymentGroupID) (*chain.Order, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	orders, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if order.Group.DeploymentGroupID.Compare(id) == 0 {
+			return order, nil
+		}
+	}
+	return nil, errors.Errorf("order %s not found", id)
+}
+
+func (s *fileSource) Bid(id types.DeploymentGroupID, price uint64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	orders, err := s.load()
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if order.Group.DeploymentGroupID.Compare(id) != 0 {
+			continue
+		}
+		if order.Group.State != types.DeploymentGroup_OPEN {
+			return errors.Errorf("order %s is not open", id)
+		}
+		order.Bids = append(order.Bids, &chain.Bid{Provider: s.provider, Price: price})
+
+		data, err := json.MarshalIndent(orders, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "json marshal")
+		}
+		return errors.Wrap(ioutil.WriteFile(s.path, data, 0644), "save orders")
+	}
+	return errors.Errorf("order %s not found", id)
+}
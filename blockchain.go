@@ -0,0 +1,451 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/daemon"
+	"github.com/Ankr-network/dccn-daemon/metering"
+	dccntypes "github.com/Ankr-network/dccn-daemon/types"
+	dccnbase "github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// bcFlags are the tendermint settings shared by the bc commands
+type bcFlags struct {
+	ns         *string
+	server     *string
+	port       *uint32
+	wsEndpoint *string
+	output     *string
+}
+
+func (f *bcFlags) client() *client.HTTP {
+	return client.NewHTTP(fmt.Sprintf("tcp://%s:%d", *f.server, *f.port), *f.wsEndpoint)
+}
+
+// query runs the abci query at the height, the last one if 0, and returns
+// the value with the height queried
+func (f *bcFlags) query(path string, data []byte, height int64) ([]byte, int64, error) {
+	res, err := f.client().ABCIQueryWithOptions(path, cmn.HexBytes(data), client.ABCIQueryOptions{Height: height})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "query")
+	}
+	if res.Response.Code != chain.CodeOK {
+		return nil, 0, errors.Errorf("query %s: %s", path, res.Response.Log)
+	}
+	return res.Response.Value, res.Response.Height, nil
+}
+
+// print writes the v as json, or the table rows
+func (f *bcFlags) print(v interface{}, header string, rows func(w *tabwriter.Writer)) error {
+	switch *f.output {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, header)
+		rows(w)
+		return w.Flush()
+	default:
+		return errors.Errorf("unknown output: %s", *f.output)
+	}
+}
+
+// txView is a chain tx with its verified payload
+type txView struct {
+	Height     int64              `json:"height,omitempty"`
+	Type       string             `json:"type"`
+	DataCenter string             `json:"dataCenter,omitempty"`
+	Namespace  string             `json:"namespace,omitempty"`
+	Seq        uint64             `json:"seq,omitempty"`
+	Start      time.Time          `json:"start"`
+	End        time.Time          `json:"end"`
+	Signer     dccnbase.Bytes     `json:"signer"`
+	Reports    []*metering.Report `json:"reports,omitempty"`
+	Payload    interface{}        `json:"payload,omitempty"`
+}
+
+// decodeTx verifies the tx signed by the address, any if empty
+func decodeTx(tx []byte, height int64, address dccnbase.Bytes) (*txView, error) {
+	envelope, err := dccntypes.DecodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := envelope.Verify(address); err != nil {
+		return nil, err
+	}
+
+	view := &txView{
+		Height:     height,
+		Type:       envelope.Type.String(),
+		DataCenter: envelope.DataCenter,
+		Namespace:  envelope.Namespace,
+		Seq:        envelope.Seq,
+		Signer:     envelope.Address(),
+	}
+	view.Start, view.End = envelope.Period()
+
+	switch envelope.Type {
+	case dccntypes.TxEnvelope_METERING:
+		err = json.Unmarshal(envelope.Payload, &view.Reports)
+	case dccntypes.TxEnvelope_CREATE_DEPLOYMENT:
+		msg := &dccntypes.TxCreateDeployment{}
+		err = msg.Unmarshal(envelope.Payload)
+		view.Payload = msg
+	case dccntypes.TxEnvelope_CLOSE_DEPLOYMENT:
+		msg := &dccntypes.TxCloseDeployment{}
+		err = msg.Unmarshal(envelope.Payload)
+		view.Payload = msg
+	case dccntypes.TxEnvelope_CREATE_BID:
+		msg := &chain.TxCreateBid{}
+		err = json.Unmarshal(envelope.Payload, msg)
+		view.Payload = msg
+	case dccntypes.TxEnvelope_REGISTER_PROVIDER:
+		msg := &chain.TxRegisterProvider{}
+		err = json.Unmarshal(envelope.Payload, msg)
+		view.Payload = msg
+	}
+	return view, errors.Wrapf(err, "%s payload", envelope.Type)
+}
+
+// header is the table header of the rows, a row per task of the metering
+// reports, a single row of the other txs
+func (v *txView) header() string {
+	if v.Type == dccntypes.TxEnvelope_METERING.String() {
+		return "HEIGHT\tTYPE\tKEY\tSEQ\tSIGNER\tTIME\tTASK\tREQUESTED CPU\tREQUESTED MEMORY\tCONSUMED CPU"
+	}
+	return "HEIGHT\tTYPE\tKEY\tSEQ\tSIGNER"
+}
+
+func (v *txView) rows(w *tabwriter.Writer) {
+	if v.Type != dccntypes.TxEnvelope_METERING.String() {
+		fmt.Fprintf(w, "%d\t%s\t%s:%s\t%d\t%s\n", v.Height, v.Type, v.DataCenter, v.Namespace, v.Seq, v.Signer)
+		return
+	}
+	for _, report := range v.Reports {
+		for name, task := range report.Tasks {
+			fmt.Fprintf(w, "%d\t%s\t%s:%s\t%d\t%s\t%s\t%s\t%d\t%d\t%d\n", v.Height, v.Type, v.DataCenter, v.Namespace, v.Seq, v.Signer,
+				report.Time.Format(time.RFC3339), name, task.Requested.CPU, task.Requested.Memory, task.Consumed.CPU)
+		}
+	}
+}
+
+func blockchainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bc",
+		Short: "blockchain service",
+		Long:  "query the messages on blockchain",
+	}
+
+	ns := cmd.PersistentFlags().StringP("namespace", "n", apiv1.NamespaceDefault, "kubernetes namespace")
+	keyPath := cmd.PersistentFlags().String("provider-key", providerKey, "ed25519 key signing the metering reports")
+
+	bc := &bcFlags{
+		ns:         ns,
+		server:     cmd.PersistentFlags().StringP("tendermint-server", "s", "127.0.0.1", "special tendermint grpc server"),
+		port:       cmd.PersistentFlags().Uint32P("tendermint-port", "p", 26657, "special tendermint grpc port"),
+		wsEndpoint: cmd.PersistentFlags().StringP("tendermint-websocket-endpoint", "w", "/websocket", "special tendermint websocket endpoint"),
+		output:     cmd.PersistentFlags().StringP("output", "o", "table", "output format: table, json"),
+	}
+
+	// the daemon alone writes the metering of its namespaces, the txs of
+	// the key follow its sequences
+	cmd.AddCommand(&cobra.Command{
+		Use:   "metering <dc-name>",
+		Short: "show the metering info on tendermint",
+		Long:  "show the last metering tx the daemon of a datacenter namespace committed into tendermint",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tx, height, err := bc.query(chain.QueryLatest, []byte(daemon.TendermintKey(args[0], *ns)), 0)
+			exitOnErr(err)
+			view, err := decodeTx(tx, height, nil)
+			exitOnErr(err)
+			exitOnErr(bc.print(view, view.header(), view.rows))
+		},
+	})
+
+	keyCmd := &cobra.Command{
+		Use:   "key",
+		Short: "show the provider key",
+		Long:  "show the provider address and public key to register, or generate the key with --generate",
+	}
+	generate := keyCmd.Flags().Bool("generate", false, "generate the key into --provider-key, which must not exist")
+	keyCmd.Run = func(cmd *cobra.Command, args []string) {
+		var key ed25519.PrivKeyEd25519
+		var err error
+		if *generate {
+			key, err = daemon.GenerateKey(*keyPath)
+		} else {
+			key, err = daemon.LoadKey(*keyPath)
+		}
+		exitOnErr(err)
+
+		pub := key.PubKey().(ed25519.PubKeyEd25519)
+		fmt.Println("Address:", dccntypes.Address(pub))
+		fmt.Println("PubKey:", dccnbase.Bytes(pub[:]))
+	}
+	cmd.AddCommand(keyCmd)
+
+	register := &cobra.Command{
+		Use:   "register <dc-name> <provider address>",
+		Short: "register the provider of a datacenter",
+		Long:  "register the provider address signing the metering of a datacenter, by a tx of an admin of the genesis",
+		Args:  cobra.MinimumNArgs(2),
+	}
+	adminKey := register.Flags().String("admin-key", "", "ed25519 key of an admin of the genesis")
+	register.Run = func(cmd *cobra.Command, args []string) {
+		provider, err := dccnbase.DecodeString(args[1])
+		exitOnErr(err)
+		key, err := daemon.LoadKey(*adminKey)
+		exitOnErr(err)
+
+		payload, err := json.Marshal(&chain.TxRegisterProvider{DataCenter: args[0], Provider: provider})
+		exitOnErr(err)
+		envelope := &dccntypes.TxEnvelope{
+			Type:       dccntypes.TxEnvelope_REGISTER_PROVIDER,
+			Version:    dccntypes.TxVersion,
+			DataCenter: args[0],
+			Payload:    payload,
+		}
+		exitOnErr(envelope.Sign(key))
+		tx, err := dccntypes.EncodeTx(envelope)
+		exitOnErr(err)
+
+		res, err := bc.client().BroadcastTxCommit(tx)
+		exitOnErr(err)
+		if res.CheckTx.Code != chain.CodeOK {
+			exitOnErr(errors.Errorf("register code %d: %s", res.CheckTx.Code, res.CheckTx.Log))
+		}
+		if res.DeliverTx.Code != chain.CodeOK {
+			exitOnErr(errors.Errorf("register code %d: %s", res.DeliverTx.Code, res.DeliverTx.Log))
+		}
+		fmt.Printf("%s registered at height %d\n", args[0], res.Height)
+	}
+	cmd.AddCommand(register)
+
+	verify := &cobra.Command{
+		Use:   "verify <dc-name>",
+		Short: "verify metering on tendermint",
+		Long:  "verify the signature of the last metering tx of a datacenter, or of the tx by hash, on tendermint",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	address := verify.Flags().String("address", "", "provider address the tx must be signed by, required")
+	hash := verify.Flags().String("hash", "", "hash of the tx to verify, the last one if empty")
+	verify.Run = func(cmd *cobra.Command, args []string) {
+		addr, err := dccnbase.DecodeString(*address)
+		exitOnErr(err)
+		if len(addr) == 0 {
+			exitOnErr(errors.New("--address must set"))
+		}
+
+		var tx []byte
+		var height int64
+		if *hash != "" {
+			h, err := dccnbase.DecodeString(*hash)
+			exitOnErr(err)
+			res, err := bc.client().Tx(h, false)
+			exitOnErr(err)
+			tx, height = res.Tx, res.Height
+		} else {
+			tx, height, err = bc.query(chain.QueryLatest, []byte(daemon.TendermintKey(args[0], *ns)), 0)
+			exitOnErr(err)
+		}
+
+		view, err := decodeTx(tx, height, addr)
+		exitOnErr(err)
+		exitOnErr(bc.print(view, view.header(), view.rows))
+	}
+	cmd.AddCommand(verify)
+
+	cmd.AddCommand(bcQueryCmd(bc))
+	cmd.AddCommand(bcHistoryCmd(bc))
+	cmd.AddCommand(&cobra.Command{
+		Use:   "tx <hash>",
+		Short: "show a tx",
+		Long:  "show and verify a committed tx by hash",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			h, err := dccnbase.DecodeString(args[0])
+			exitOnErr(err)
+			res, err := bc.client().Tx(h, false)
+			exitOnErr(err)
+
+			view, err := decodeTx(res.Tx, res.Height, nil)
+			exitOnErr(err)
+			exitOnErr(bc.print(view, view.header(), view.rows))
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "show the chain status",
+		Long:  "show the status of the tendermint node and of the application",
+		Run: func(cmd *cobra.Command, args []string) {
+			c := bc.client()
+			status, err := c.Status()
+			exitOnErr(err)
+			info, err := c.ABCIInfo()
+			exitOnErr(err)
+
+			exitOnErr(bc.print(map[string]interface{}{
+				"node": status.NodeInfo,
+				"sync": status.SyncInfo,
+				"app":  info.Response,
+			}, "NODE\tNETWORK\tHEIGHT\tTIME\tCATCHING UP\tAPP HEIGHT\tAPP HASH", func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%t\t%d\t%X\n", status.NodeInfo.ID(), status.NodeInfo.Network,
+					status.SyncInfo.LatestBlockHeight, status.SyncInfo.LatestBlockTime.Format(time.RFC3339),
+					status.SyncInfo.CatchingUp, info.Response.LastBlockHeight, info.Response.LastBlockAppHash)
+			}))
+		},
+	})
+
+	return cmd
+}
+
+func bcQueryCmd(bc *bcFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "query the chain state",
+	}
+
+	meteringCmd := &cobra.Command{
+		Use:   "metering <dc-name>",
+		Short: "query the last metering of a datacenter namespace",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	height := meteringCmd.Flags().Int64("height", 0, "query the state at the height, the last one if 0")
+	meteringCmd.Run = func(cmd *cobra.Command, args []string) {
+		tx, at, err := bc.query(chain.QueryLatest, []byte(daemon.TendermintKey(args[0], *bc.ns)), *height)
+		exitOnErr(err)
+		view, err := decodeTx(tx, at, nil)
+		exitOnErr(err)
+		exitOnErr(bc.print(view, view.header(), view.rows))
+	}
+	cmd.AddCommand(meteringCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "deployment <address>",
+		Short: "query a deployment",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			address, err := dccnbase.DecodeString(args[0])
+			exitOnErr(err)
+			data, _, err := bc.query(chain.QueryDeployment, address, 0)
+			exitOnErr(err)
+
+			deployment := &dccntypes.Deployment{}
+			exitOnErr(json.Unmarshal(data, deployment))
+			exitOnErr(bc.print(deployment, "ADDRESS\tTENANT\tSTATE\tVERSION", func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", deployment.Address, deployment.Tenant, deployment.State, deployment.Version)
+			}))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "provider <dc-name>",
+		Short: "query the provider registered for a datacenter",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, _, err := bc.query(chain.QueryProvider, []byte(args[0]), 0)
+			exitOnErr(err)
+
+			var provider dccnbase.Bytes
+			exitOnErr(json.Unmarshal(data, &provider))
+			exitOnErr(bc.print(provider, "DATACENTER\tPROVIDER", func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "%s\t%s\n", args[0], provider)
+			}))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "orders [<deployment/seq>]",
+		Short: "query the open orders, or an order in any state",
+		Run: func(cmd *cobra.Command, args []string) {
+			var orders []*chain.Order
+			if len(args) > 0 {
+				data, _, err := bc.query(chain.QueryOrder, []byte(args[0]), 0)
+				exitOnErr(err)
+				order := &chain.Order{}
+				exitOnErr(json.Unmarshal(data, order))
+				orders = append(orders, order)
+			} else {
+				data, _, err := bc.query(chain.QueryOrders, nil, 0)
+				exitOnErr(err)
+				exitOnErr(json.Unmarshal(data, &orders))
+			}
+
+			exitOnErr(bc.print(orders, "ORDER\tNAME\tSTATE\tHEIGHT\tTTL\tGROUPS\tMAX PRICE\tBIDS\tWINNER", func(w *tabwriter.Writer) {
+				for _, order := range orders {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n", order.Group.DeploymentGroupID, order.Group.Name,
+						order.Group.State, order.Height, order.Group.OrderTTL, len(order.Group.Resources), order.MaxPrice(),
+						len(order.Bids), order.Winner)
+				}
+			}))
+		},
+	})
+
+	return cmd
+}
+
+func bcHistoryCmd(bc *bcFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history <dc-name>",
+		Short: "query the metering history of a datacenter",
+		Long:  "query the metering txs of a datacenter, of all its namespaces unless --namespace is set, by period",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	from := cmd.Flags().String("from", "", "start of the period, RFC3339, unbounded if empty")
+	to := cmd.Flags().String("to", "", "end of the period, RFC3339, unbounded if empty")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		query := &chain.HistoryQuery{DataCenter: args[0]}
+		if cmd.Flags().Changed("namespace") {
+			query.Namespace = *bc.ns
+		}
+		var err error
+		if *from != "" {
+			query.From, err = time.Parse(time.RFC3339, *from)
+			exitOnErr(err)
+		}
+		if *to != "" {
+			query.To, err = time.Parse(time.RFC3339, *to)
+			exitOnErr(err)
+		}
+
+		data, err := json.Marshal(query)
+		exitOnErr(err)
+		data, _, err = bc.query(chain.QueryHistory, data, 0)
+		exitOnErr(err)
+		var entries []*chain.HistoryEntry
+		exitOnErr(json.Unmarshal(data, &entries))
+
+		views := make([]*txView, 0, len(entries))
+		for _, entry := range entries {
+			view, err := decodeTx(entry.Tx, entry.Height, nil)
+			exitOnErr(err)
+			views = append(views, view)
+		}
+		exitOnErr(bc.print(views, "HEIGHT\tKEY\tSEQ\tSTART\tEND\tREPORTS\tSIGNER", func(w *tabwriter.Writer) {
+			for _, view := range views {
+				fmt.Fprintf(w, "%d\t%s:%s\t%d\t%s\t%s\t%d\t%s\n", view.Height, view.DataCenter, view.Namespace, view.Seq,
+					view.Start.Format(time.RFC3339), view.End.Format(time.RFC3339), len(view.Reports), view.Signer)
+			}
+		}))
+	}
+
+	return cmd
+}
@@ -0,0 +1,288 @@
+// Package chain is the tendermint ABCI application of the metering, the
+// deployment, the bid and the provider txs
+package chain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// Codes of the rejected txs
+const (
+	CodeOK uint32 = iota
+	CodeInvalid
+	CodeUnauthorized
+	CodeDuplicate
+)
+
+// TagKey tags the metering txs with the key of the datacenter namespace
+const TagKey = "metering.key"
+
+type codeError struct {
+	code uint32
+	error
+}
+
+func withCode(code uint32, err error) error {
+	return &codeError{code: code, error: err}
+}
+
+func errorCode(err error) uint32 {
+	if err, ok := err.(*codeError); ok {
+		return err.code
+	}
+	return CodeInvalid
+}
+
+// App keeps the state in memory, tendermint replays the blocks on start as
+// the height of the app is 0
+type App struct {
+	abci.BaseApplication
+	sync.Mutex
+
+	height  int64
+	appHash []byte
+	// tx hashes delivered in the block
+	block [][]byte
+
+	// addresses registering the providers
+	admins []base.Bytes
+	// provider address of each datacenter, registered by an admin
+	providers map[string]base.Bytes
+	// last record sequence of each datacenter namespace
+	seqs    map[string]uint64
+	history map[string][]*HistoryEntry
+
+	deployments map[string]*types.Deployment
+	// order of each deployment group, by group id
+	orders map[string]*Order
+}
+
+func NewApp() *App {
+	return &App{
+		providers:   map[string]base.Bytes{},
+		seqs:        map[string]uint64{},
+		history:     map[string][]*HistoryEntry{},
+		deployments: map[string]*types.Deployment{},
+		orders:      map[string]*Order{},
+	}
+}
+
+func (a *App) Info(req abci.RequestInfo) abci.ResponseInfo {
+	a.Lock()
+	defer a.Unlock()
+	return abci.ResponseInfo{
+		Data:             "dccn",
+		AppVersion:       types.TxVersion,
+		LastBlockHeight:  a.height,
+		LastBlockAppHash: a.appHash,
+	}
+}
+
+func (a *App) CheckTx(tx []byte) abci.ResponseCheckTx {
+	a.Lock()
+	defer a.Unlock()
+
+	if _, err := a.check(tx); err != nil {
+		return abci.ResponseCheckTx{Code: errorCode(err), Log: err.Error()}
+	}
+	return abci.ResponseCheckTx{Code: CodeOK}
+}
+
+func (a *App) DeliverTx(tx []byte) abci.ResponseDeliverTx {
+	a.Lock()
+	defer a.Unlock()
+
+	envelope, err := a.check(tx)
+	if err != nil {
+		return abci.ResponseDeliverTx{Code: errorCode(err), Log: err.Error()}
+	}
+
+	var tags []cmn.KVPair
+	switch envelope.Type {
+	case types.TxEnvelope_METERING:
+		a.deliverMetering(envelope, tx)
+		tags = append(tags, cmn.KVPair{Key: []byte(TagKey), Value: []byte(envelope.Key())})
+	case types.TxEnvelope_CREATE_DEPLOYMENT:
+		a.deliverCreate(envelope)
+	case types.TxEnvelope_CLOSE_DEPLOYMENT:
+		a.deliverClose(envelope)
+	case types.TxEnvelope_CREATE_BID:
+		a.deliverBid(envelope)
+	case types.TxEnvelope_REGISTER_PROVIDER:
+		a.deliverRegister(envelope)
+	}
+
+	hash := sha256.Sum256(tx)
+	a.block = append(a.block, hash[:])
+	return abci.ResponseDeliverTx{Code: CodeOK, Tags: tags}
+}
+
+func (a *App) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	a.Lock()
+	defer a.Unlock()
+	a.height = req.Header.Height
+	a.block = nil
+	return abci.ResponseBeginBlock{}
+}
+
+// Commit chains the app hash with the txs delivered in the block
+func (a *App) Commit() abci.ResponseCommit {
+	a.Lock()
+	defer a.Unlock()
+
+	if len(a.block) > 0 {
+		h := sha256.New()
+		h.Write(a.appHash)
+		for _, hash := range a.block {
+			h.Write(hash)
+		}
+		a.appHash = h.Sum(nil)
+		a.block = nil
+	}
+	return abci.ResponseCommit{Data: a.appHash}
+}
+
+// check validates the tx against the committed state
+func (a *App) check(tx []byte) (*types.TxEnvelope, error) {
+	envelope, err := types.DecodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := envelope.Verify(nil); err != nil {
+		return nil, withCode(CodeUnauthorized, err)
+	}
+
+	switch envelope.Type {
+	case types.TxEnvelope_METERING:
+		return envelope, a.checkMetering(envelope)
+	case types.TxEnvelope_CREATE_DEPLOYMENT:
+		_, err := a.checkCreate(envelope)
+		return envelope, err
+	case types.TxEnvelope_CLOSE_DEPLOYMENT:
+		_, err := a.checkClose(envelope)
+		return envelope, err
+	case types.TxEnvelope_CREATE_BID:
+		_, _, err := a.checkBid(envelope)
+		return envelope, err
+	case types.TxEnvelope_REGISTER_PROVIDER:
+		_, err := a.checkRegister(envelope)
+		return envelope, err
+	default:
+		return nil, errors.Errorf("unknown tx type %s", envelope.Type)
+	}
+}
+
+func (a *App) checkMetering(envelope *types.TxEnvelope) error {
+	key := envelope.Key()
+	address, ok := a.providers[envelope.DataCenter]
+	if !ok {
+		return withCode(CodeUnauthorized, errors.Errorf("datacenter %s has no provider registered", envelope.DataCenter))
+	}
+	if !address.Equal(envelope.Address()) {
+		return withCode(CodeUnauthorized, errors.Errorf("%s is signed by %s", key, address))
+	}
+	if envelope.Seq <= a.seqs[key] {
+		return withCode(CodeDuplicate, errors.Errorf("sequence %d of %s is committed", envelope.Seq, key))
+	}
+
+	var reports []*metering.Report
+	if err := json.Unmarshal(envelope.Payload, &reports); err != nil {
+		return errors.Wrap(err, "metering payload")
+	}
+	if len(reports) == 0 {
+		return errors.New("no metering reports")
+	}
+	for _, report := range reports {
+		if report.DataCenter != envelope.DataCenter || report.Namespace != envelope.Namespace {
+			return errors.Errorf("report of %s:%s in the tx of %s", report.DataCenter, report.Namespace, key)
+		}
+	}
+	return nil
+}
+
+func (a *App) deliverMetering(envelope *types.TxEnvelope, tx []byte) {
+	key := envelope.Key()
+	var reports []json.RawMessage
+	json.Unmarshal(envelope.Payload, &reports)
+
+	start, end := envelope.Period()
+	a.seqs[key] = envelope.Seq + uint64(len(reports)) - 1
+	a.history[key] = append(a.history[key], &HistoryEntry{
+		Height: a.height,
+		Seq:    envelope.Seq,
+		Start:  start,
+		End:    end,
+		Tx:     tx,
+	})
+}
+
+// DeploymentAddress is the address of the deployment created by the tenant
+// with the nonce
+func DeploymentAddress(tenant base.Bytes, nonce uint64) base.Bytes {
+	buf := make([]byte, len(tenant)+8)
+	copy(buf, tenant)
+	binary.BigEndian.PutUint64(buf[len(tenant):], nonce)
+	return base.Bytes(crypto.AddressHash(buf))
+}
+
+func (a *App) checkCreate(envelope *types.TxEnvelope) (*types.TxCreateDeployment, error) {
+	msg := &types.TxCreateDeployment{}
+	if err := msg.Unmarshal(envelope.Payload); err != nil {
+		return nil, errors.Wrap(err, "create deployment payload")
+	}
+	if !msg.Tenant.Equal(envelope.Address()) {
+		return nil, withCode(CodeUnauthorized, errors.Errorf("tenant %s is not the signer", msg.Tenant))
+	}
+	address := DeploymentAddress(msg.Tenant, msg.Nonce)
+	if _, ok := a.deployments[address.String()]; ok {
+		return nil, withCode(CodeDuplicate, errors.Errorf("deployment %s exists", address))
+	}
+	return msg, nil
+}
+
+func (a *App) deliverCreate(envelope *types.TxEnvelope) {
+	msg, _ := a.checkCreate(envelope)
+	address := DeploymentAddress(msg.Tenant, msg.Nonce)
+	a.deployments[address.String()] = &types.Deployment{
+		Address: address,
+		Tenant:  msg.Tenant,
+		State:   types.Deployment_ACTIVE,
+		Version: msg.Version,
+	}
+	a.openOrders(address, msg)
+}
+
+func (a *App) checkClose(envelope *types.TxEnvelope) (*types.Deployment, error) {
+	msg := &types.TxCloseDeployment{}
+	if err := msg.Unmarshal(envelope.Payload); err != nil {
+		return nil, errors.Wrap(err, "close deployment payload")
+	}
+	deployment, ok := a.deployments[msg.Deployment.String()]
+	if !ok {
+		return nil, errors.Errorf("deployment %s not found", msg.Deployment)
+	}
+	if !deployment.Tenant.Equal(envelope.Address()) {
+		return nil, withCode(CodeUnauthorized, errors.Errorf("tenant %s is not the signer", deployment.Tenant))
+	}
+	if deployment.State == types.Deployment_CLOSED {
+		return nil, withCode(CodeDuplicate, errors.Errorf("deployment %s is closed", msg.Deployment))
+	}
+	return deployment, nil
+}
+
+func (a *App) deliverClose(envelope *types.TxEnvelope) {
+	deployment, _ := a.checkClose(envelope)
+	deployment.State = types.Deployment_CLOSED
+	a.closeOrders(deployment.Address)
+}
@@ -0,0 +1,208 @@
+package chain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func meteringTx(t *testing.T, key ed25519.PrivKeyEd25519, ns string, seq uint64, start time.Time) []byte {
+	payload, err := json.Marshal([]*metering.Report{{DataCenter: "dc", Namespace: ns, Time: start.Add(time.Minute)}})
+	require.NoError(t, err)
+
+	envelope := &types.TxEnvelope{
+		Type:       types.TxEnvelope_METERING,
+		Version:    types.TxVersion,
+		DataCenter: "dc",
+		Namespace:  ns,
+		Seq:        seq,
+		Payload:    payload,
+	}
+	envelope.SetPeriod(start, start.Add(time.Minute))
+	require.NoError(t, envelope.Sign(key))
+	tx, err := types.EncodeTx(envelope)
+	require.NoError(t, err)
+	return tx
+}
+
+func deliver(t *testing.T, app *chain.App, height int64, txs ...[]byte) {
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: height}})
+	for _, tx := range txs {
+		res := app.DeliverTx(tx)
+		require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	}
+	app.EndBlock(abci.RequestEndBlock{Height: height})
+	app.Commit()
+}
+
+// genesis registers the key as the provider of dc
+func genesis(t *testing.T, app *chain.App, admin, key ed25519.PrivKeyEd25519) {
+	state, err := json.Marshal(&chain.Genesis{
+		Admins:    []base.Bytes{types.Address(admin.PubKey())},
+		Providers: map[string]base.Bytes{"dc": types.Address(key.PubKey())},
+	})
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{AppStateBytes: state})
+}
+
+func registerTx(t *testing.T, admin ed25519.PrivKeyEd25519, provider base.Bytes) []byte {
+	payload, err := json.Marshal(&chain.TxRegisterProvider{DataCenter: "dc", Provider: provider})
+	require.NoError(t, err)
+
+	envelope := &types.TxEnvelope{
+		Type:    types.TxEnvelope_REGISTER_PROVIDER,
+		Version: types.TxVersion,
+		Payload: payload,
+	}
+	require.NoError(t, envelope.Sign(admin))
+	tx, err := types.EncodeTx(envelope)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestApp_Metering(t *testing.T) {
+	app := chain.NewApp()
+	key := ed25519.GenPrivKey()
+	genesis(t, app, ed25519.GenPrivKey(), key)
+	start := time.Unix(1550000000, 0).UTC()
+
+	first := meteringTx(t, key, "default", 1, start)
+	deliver(t, app, 1, first, meteringTx(t, key, "jobs", 1, start))
+	deliver(t, app, 2, meteringTx(t, key, "default", 2, start.Add(time.Minute)))
+
+	assert.Equal(t, chain.CodeDuplicate, app.CheckTx(first).Code)
+	assert.Equal(t, chain.CodeUnauthorized, app.CheckTx(meteringTx(t, ed25519.GenPrivKey(), "default", 3, start)).Code)
+	assert.Equal(t, chain.CodeInvalid, app.CheckTx([]byte("dc:default={}")).Code)
+	assert.Equal(t, int64(2), app.Info(abci.RequestInfo{}).LastBlockHeight)
+
+	res := app.Query(abci.RequestQuery{Path: chain.QueryLatest, Data: []byte("dc:default")})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	latest, err := types.DecodeTx(res.Value)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), latest.Seq)
+
+	res = app.Query(abci.RequestQuery{Path: chain.QueryLatest, Data: []byte("dc:default"), Height: 1})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	assert.Equal(t, int64(1), res.Height)
+	assert.Equal(t, first, res.Value)
+
+	res = app.Query(abci.RequestQuery{Path: chain.QuerySeq, Data: []byte("dc:default")})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	assert.Equal(t, "2", string(res.Value))
+
+	query, err := json.Marshal(&chain.HistoryQuery{DataCenter: "dc", From: start.Add(30 * time.Second)})
+	require.NoError(t, err)
+	res = app.Query(abci.RequestQuery{Path: chain.QueryHistory, Data: query})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	var history []*chain.HistoryEntry
+	require.NoError(t, json.Unmarshal(res.Value, &history))
+	require.Len(t, history, 3)
+	assert.Equal(t, int64(2), history[2].Height)
+}
+
+func TestApp_Provider(t *testing.T) {
+	app := chain.NewApp()
+	start := time.Unix(1550000000, 0).UTC()
+	assert.Equal(t, chain.CodeUnauthorized, app.CheckTx(meteringTx(t, ed25519.GenPrivKey(), "default", 1, start)).Code)
+
+	admin, key, next := ed25519.GenPrivKey(), ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	genesis(t, app, admin, key)
+	deliver(t, app, 1, meteringTx(t, key, "default", 1, start))
+
+	// only an admin registers the providers
+	assert.Equal(t, chain.CodeUnauthorized, app.CheckTx(registerTx(t, key, types.Address(key.PubKey()))).Code)
+	deliver(t, app, 2, registerTx(t, admin, types.Address(next.PubKey())))
+	assert.Equal(t, chain.CodeUnauthorized, app.CheckTx(meteringTx(t, key, "default", 2, start)).Code)
+	assert.Equal(t, chain.CodeOK, app.CheckTx(meteringTx(t, next, "default", 2, start)).Code)
+
+	res := app.Query(abci.RequestQuery{Path: chain.QueryProvider, Data: []byte("dc")})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	assert.Equal(t, `"`+types.Address(next.PubKey()).String()+`"`, string(res.Value))
+}
+
+func TestApp_Deployment(t *testing.T) {
+	app := chain.NewApp()
+	tenant := ed25519.GenPrivKey()
+	address := types.Address(tenant.PubKey())
+
+	envelope := func(txType types.TxEnvelope_Type, msg interface{ Marshal() ([]byte, error) }) []byte {
+		payload, err := msg.Marshal()
+		require.NoError(t, err)
+		envelope := &types.TxEnvelope{Type: txType, Version: types.TxVersion, Payload: payload}
+		require.NoError(t, envelope.Sign(tenant))
+		tx, err := types.EncodeTx(envelope)
+		require.NoError(t, err)
+		return tx
+	}
+
+	create := envelope(types.TxEnvelope_CREATE_DEPLOYMENT, &types.TxCreateDeployment{Tenant: address, Nonce: 1})
+	deliver(t, app, 1, create)
+	assert.Equal(t, chain.CodeDuplicate, app.CheckTx(create).Code)
+
+	deployment := chain.DeploymentAddress(address, 1)
+	deliver(t, app, 2, envelope(types.TxEnvelope_CLOSE_DEPLOYMENT, &types.TxCloseDeployment{Deployment: deployment}))
+
+	res := app.Query(abci.RequestQuery{Path: chain.QueryDeployment, Data: deployment})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	result := &types.Deployment{}
+	require.NoError(t, json.Unmarshal(res.Value, result))
+	assert.Equal(t, types.Deployment_CLOSED, result.State)
+}
+
+func TestApp_Order(t *testing.T) {
+	app := chain.NewApp()
+	tenant := ed25519.GenPrivKey()
+	address := types.Address(tenant.PubKey())
+
+	payload, err := (&types.TxCreateDeployment{Tenant: address, Nonce: 1, OrderTTL: 2, Groups: []*types.GroupSpec{{
+		Name:      "web",
+		Resources: []types.ResourceGroup{{Unit: types.ResourceUnit{CPU: 100}, Count: 2, Price: 10}},
+	}}}).Marshal()
+	require.NoError(t, err)
+	create := &types.TxEnvelope{Type: types.TxEnvelope_CREATE_DEPLOYMENT, Version: types.TxVersion, Payload: payload}
+	require.NoError(t, create.Sign(tenant))
+	tx, err := types.EncodeTx(create)
+	require.NoError(t, err)
+	deliver(t, app, 1, tx)
+
+	group := types.DeploymentGroupID{Deployment: chain.DeploymentAddress(address, 1), Seq: 1}
+	bid := func(key ed25519.PrivKeyEd25519, price uint64) []byte {
+		payload, err := json.Marshal(&chain.TxCreateBid{Group: group, Price: price})
+		require.NoError(t, err)
+		envelope := &types.TxEnvelope{Type: types.TxEnvelope_CREATE_BID, Version: types.TxVersion, Payload: payload}
+		require.NoError(t, envelope.Sign(key))
+		tx, err := types.EncodeTx(envelope)
+		require.NoError(t, err)
+		return tx
+	}
+
+	res := app.Query(abci.RequestQuery{Path: chain.QueryOrders})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	var orders []*chain.Order
+	require.NoError(t, json.Unmarshal(res.Value, &orders))
+	require.Len(t, orders, 1)
+	assert.Equal(t, group, orders[0].Group.DeploymentGroupID)
+
+	low, high := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	assert.Equal(t, chain.CodeInvalid, app.CheckTx(bid(low, 21)).Code)
+	deliver(t, app, 2, bid(high, 20), bid(low, 15))
+	assert.Equal(t, chain.CodeDuplicate, app.CheckTx(bid(low, 10)).Code)
+	deliver(t, app, 3)
+
+	res = app.Query(abci.RequestQuery{Path: chain.QueryOrder, Data: []byte(group.String())})
+	require.Equal(t, chain.CodeOK, res.Code, res.Log)
+	order := &chain.Order{}
+	require.NoError(t, json.Unmarshal(res.Value, order))
+	assert.Equal(t, types.DeploymentGroup_ORDERED, order.Group.State)
+	assert.Equal(t, types.Address(low.PubKey()), order.Winner)
+	assert.Equal(t, chain.CodeInvalid, app.CheckTx(bid(ed25519.GenPrivKey(), 10)).Code)
+}
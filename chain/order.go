@@ -0,0 +1,159 @@
+package chain
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// DefaultOrderTTL is the blocks an order is open if the deployment sets none
+const DefaultOrderTTL int64 = 10
+
+// TxCreateBid is the payload of the CREATE_BID txs, the provider is the
+// signer
+type TxCreateBid struct {
+	Group types.DeploymentGroupID `json:"group"`
+	// Price of the resource groups per hour, the sum of the units
+	Price uint64 `json:"price"`
+}
+
+// Bid is the offer of a provider for an order
+type Bid struct {
+	Provider base.Bytes `json:"provider"`
+	Price    uint64     `json:"price"`
+	Height   int64      `json:"height"`
+}
+
+// Order is a deployment group open to the bids of the providers. The lowest
+// bid, the first on a tie, wins when the order closes OrderTTL blocks after
+// it opened, and the group is ORDERED. The group is CLOSED without bids.
+type Order struct {
+	Group types.DeploymentGroup `json:"group"`
+	// Height the order opened at
+	Height int64      `json:"height"`
+	Bids   []*Bid     `json:"bids,omitempty"`
+	Winner base.Bytes `json:"winner,omitempty"`
+}
+
+// Open returns whether the order takes bids at the height
+func (o *Order) Open(height int64) bool {
+	return o.Group.State == types.DeploymentGroup_OPEN && height < o.Height+o.Group.OrderTTL
+}
+
+// MaxPrice is the price of the resource groups per hour the tenant pays at
+// most, 0 if any group is not priced
+func (o *Order) MaxPrice() uint64 {
+	var price uint64
+	for _, group := range o.Group.Resources {
+		if group.Price == 0 {
+			return 0
+		}
+		price += group.Price * uint64(group.Count)
+	}
+	return price
+}
+
+// openOrders opens an order per group of the deployment
+func (a *App) openOrders(address base.Bytes, msg *types.TxCreateDeployment) {
+	ttl := msg.OrderTTL
+	if ttl <= 0 {
+		ttl = DefaultOrderTTL
+	}
+	for i, spec := range msg.Groups {
+		order := &Order{
+			Group: types.DeploymentGroup{
+				DeploymentGroupID: types.DeploymentGroupID{Deployment: address, Seq: uint64(i + 1)},
+				Name:              spec.Name,
+				OrderTTL:          ttl,
+				State:             types.DeploymentGroup_OPEN,
+				Requirements:      spec.Requirements,
+				Resources:         spec.Resources,
+			},
+			Height: a.height,
+		}
+		a.orders[order.Group.DeploymentGroupID.String()] = order
+	}
+}
+
+// closeOrders closes the groups of the deployment
+func (a *App) closeOrders(address base.Bytes) {
+	for _, order := range a.orders {
+		if order.Group.Deployment.Equal(address) {
+			order.Group.State = types.DeploymentGroup_CLOSED
+		}
+	}
+}
+
+func (a *App) checkBid(envelope *types.TxEnvelope) (*TxCreateBid, *Order, error) {
+	msg := &TxCreateBid{}
+	if err := json.Unmarshal(envelope.Payload, msg); err != nil {
+		return nil, nil, errors.Wrap(err, "bid payload")
+	}
+	order, ok := a.orders[msg.Group.String()]
+	if !ok {
+		return nil, nil, errors.Errorf("order %s not found", msg.Group)
+	}
+	if !order.Open(a.height) {
+		return nil, nil, errors.Errorf("order %s is not open", msg.Group)
+	}
+	if msg.Price == 0 {
+		return nil, nil, errors.New("bid of no price")
+	}
+	if max := order.MaxPrice(); max != 0 && msg.Price > max {
+		return nil, nil, errors.Errorf("price %d over the order price %d", msg.Price, max)
+	}
+	for _, bid := range order.Bids {
+		if bid.Provider.Equal(envelope.Address()) {
+			return nil, nil, withCode(CodeDuplicate, errors.Errorf("%s has bid for %s", bid.Provider, msg.Group))
+		}
+	}
+	return msg, order, nil
+}
+
+func (a *App) deliverBid(envelope *types.TxEnvelope) {
+	msg, order, _ := a.checkBid(envelope)
+	order.Bids = append(order.Bids, &Bid{Provider: envelope.Address(), Price: msg.Price, Height: a.height})
+}
+
+// EndBlock closes the orders of the TTL reached
+func (a *App) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, order := range a.orders {
+		if order.Group.State != types.DeploymentGroup_OPEN || order.Open(req.Height) {
+			continue
+		}
+		var winner *Bid
+		for _, bid := range order.Bids {
+			if winner == nil || bid.Price < winner.Price {
+				winner = bid
+			}
+		}
+		if winner == nil {
+			order.Group.State = types.DeploymentGroup_CLOSED
+			continue
+		}
+		order.Group.State = types.DeploymentGroup_ORDERED
+		order.Winner = winner.Provider
+	}
+	return abci.ResponseEndBlock{}
+}
+
+// openOrderList returns the open orders sorted by group
+func (a *App) openOrderList() []*Order {
+	orders := []*Order{}
+	for _, order := range a.orders {
+		if order.Open(a.height) {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Group.DeploymentGroupID.Compare(orders[j].Group.DeploymentGroupID) < 0
+	})
+	return orders
+}
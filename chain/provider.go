@@ -0,0 +1,69 @@
+package chain
+
+import (
+	"encoding/json"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Genesis is the app state of the genesis file, the admins register the
+// providers of the datacenters
+type Genesis struct {
+	Admins []base.Bytes `json:"admins"`
+	// provider address of each datacenter
+	Providers map[string]base.Bytes `json:"providers,omitempty"`
+}
+
+// TxRegisterProvider is the payload of the REGISTER_PROVIDER txs, signed by
+// an admin. The provider signs the metering of all the namespaces of the
+// datacenter, a new registration replaces its key.
+type TxRegisterProvider struct {
+	DataCenter string     `json:"dataCenter"`
+	Provider   base.Bytes `json:"provider"`
+}
+
+// InitChain loads the admins and the providers of the genesis
+func (a *App) InitChain(req abci.RequestInitChain) abci.ResponseInitChain {
+	a.Lock()
+	defer a.Unlock()
+
+	if len(req.AppStateBytes) == 0 {
+		return abci.ResponseInitChain{}
+	}
+	genesis := &Genesis{}
+	if err := json.Unmarshal(req.AppStateBytes, genesis); err != nil {
+		panic(errors.Wrap(err, "genesis app state"))
+	}
+	a.admins = genesis.Admins
+	for dc, provider := range genesis.Providers {
+		a.providers[dc] = provider
+	}
+	return abci.ResponseInitChain{}
+}
+
+func (a *App) checkRegister(envelope *types.TxEnvelope) (*TxRegisterProvider, error) {
+	admin := false
+	for _, address := range a.admins {
+		admin = admin || address.Equal(envelope.Address())
+	}
+	if !admin {
+		return nil, withCode(CodeUnauthorized, errors.Errorf("%s is not an admin", envelope.Address()))
+	}
+
+	msg := &TxRegisterProvider{}
+	if err := json.Unmarshal(envelope.Payload, msg); err != nil {
+		return nil, errors.Wrap(err, "register provider payload")
+	}
+	if msg.DataCenter == "" || len(msg.Provider) == 0 {
+		return nil, errors.New("register provider without datacenter or provider")
+	}
+	return msg, nil
+}
+
+func (a *App) deliverRegister(envelope *types.TxEnvelope) {
+	msg, _ := a.checkRegister(envelope)
+	a.providers[msg.DataCenter] = msg.Provider
+}
@@ -0,0 +1,135 @@
+package chain
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Query paths, of the committed state only
+const (
+	// data: datacenter:namespace, value: the last metering tx committed at or
+	// before the height of the query
+	QueryLatest = "/metering/latest"
+	// data: json HistoryQuery, value: json []HistoryEntry
+	QueryHistory = "/metering/history"
+	// data: datacenter:namespace, value: json last record sequence delivered,
+	// 0 if none
+	QuerySeq = "/metering/seq"
+	// data: deployment address, value: json types.Deployment
+	QueryDeployment = "/deployment"
+	// data: empty, value: json []Order of the open orders
+	QueryOrders = "/orders"
+	// data: deployment group id, deployment/seq, value: json Order
+	QueryOrder = "/order"
+	// data: datacenter, value: json address of its provider
+	QueryProvider = "/provider"
+)
+
+// HistoryQuery selects the metering txs of a datacenter, of all its
+// namespaces if Namespace is empty, with periods overlapping From and To
+type HistoryQuery struct {
+	DataCenter string    `json:"dataCenter"`
+	Namespace  string    `json:"namespace,omitempty"`
+	From       time.Time `json:"from,omitempty"`
+	To         time.Time `json:"to,omitempty"`
+}
+
+// HistoryEntry is a metering tx committed at the height
+type HistoryEntry struct {
+	Height int64      `json:"height"`
+	Seq    uint64     `json:"seq"`
+	Start  time.Time  `json:"start"`
+	End    time.Time  `json:"end"`
+	Tx     base.Bytes `json:"tx"`
+}
+
+func (a *App) Query(req abci.RequestQuery) abci.ResponseQuery {
+	a.Lock()
+	defer a.Unlock()
+
+	height := req.Height
+	if height <= 0 || height > a.height {
+		height = a.height
+	}
+	value, err := a.query(req.Path, req.Data, height)
+	if err != nil {
+		return abci.ResponseQuery{Code: CodeInvalid, Log: err.Error(), Height: height}
+	}
+	return abci.ResponseQuery{Code: CodeOK, Key: req.Data, Value: value, Height: height}
+}
+
+func (a *App) query(path string, data []byte, height int64) ([]byte, error) {
+	switch path {
+	case QueryLatest:
+		history := a.history[string(data)]
+		i := sort.Search(len(history), func(i int) bool { return history[i].Height > height })
+		if i == 0 {
+			return nil, errors.Errorf("no metering of %s at height %d", data, height)
+		}
+		return history[i-1].Tx, nil
+
+	case QueryHistory:
+		query := &HistoryQuery{}
+		if err := json.Unmarshal(data, query); err != nil {
+			return nil, errors.Wrap(err, "history query")
+		}
+		var keys []string
+		for key := range a.history {
+			if query.Namespace == "" && strings.HasPrefix(key, query.DataCenter+":") ||
+				key == query.DataCenter+":"+query.Namespace {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		entries := []*HistoryEntry{}
+		for _, key := range keys {
+			for _, entry := range a.history[key] {
+				if (query.From.IsZero() || entry.End.After(query.From)) &&
+					(query.To.IsZero() || entry.Start.Before(query.To)) {
+					entries = append(entries, entry)
+				}
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Height < entries[j].Height
+		})
+		return json.Marshal(entries)
+
+	case QuerySeq:
+		return json.Marshal(a.seqs[string(data)])
+
+	case QueryDeployment:
+		deployment, ok := a.deployments[base.Bytes(data).String()]
+		if !ok {
+			return nil, errors.Errorf("deployment %s not found", base.Bytes(data))
+		}
+		return json.Marshal(deployment)
+
+	case QueryOrders:
+		return json.Marshal(a.openOrderList())
+
+	case QueryOrder:
+		order, ok := a.orders[string(data)]
+		if !ok {
+			return nil, errors.Errorf("order %s not found", data)
+		}
+		return json.Marshal(order)
+
+	case QueryProvider:
+		provider, ok := a.providers[string(data)]
+		if !ok {
+			return nil, errors.Errorf("datacenter %s has no provider registered", data)
+		}
+		return json.Marshal(provider)
+
+	default:
+		return nil, errors.Errorf("unknown query path %s", path)
+	}
+}
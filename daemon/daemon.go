@@ -12,7 +12,10 @@ import (
 
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	grpc_dcmgr "github.com/Ankr-network/dccn-common/protos/dcmgr/v1/grpc"
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/metering"
 	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/types"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -28,40 +31,47 @@ type taskCtx struct {
 var dataCenterName string
 var startTimestamp uint64
 var modTimestamp uint64
-
-// ServeTask will serve the task metering with blockchain logic
-func ServeTask(cfgpath, namespace, ingressHost, hubServer, dcName,
-	tendermintServer, tendermintWsEndpoint string) error {
+var dcInventory *inventory.Inventory
+var dcOrders *Orders
+
+// ServeTask serves the tasks of the hub, with the options of their orders,
+// sends the metering of the namespace through the outbox and reports the
+// inventory in the heartbeat
+func ServeTask(tasker *task.Tasker, engine *metering.Engine, outbox *Outbox, inv *inventory.Inventory,
+	orders *Orders, hubServer, dcName string) error {
 	dataCenterName = dcName
+	dcInventory = inv
+	dcOrders = orders
 	startTimestamp = uint64(time.Now().UnixNano())
-	tasker, err := task.NewTasker(cfgpath, namespace, ingressHost)
-	if err != nil {
-		return err
-	}
 
-	go taskMetering(tasker, dcName, namespace, tendermintServer, tendermintWsEndpoint)
+	go outbox.Run(nil)
+	go taskMetering(engine, outbox, dcName, tasker.Namespace())
 
 	var taskCh = make(chan *taskCtx) // block chan, serve single task one time
 	go taskOperator(tasker, dcName, taskCh)
 	return taskReciver(tasker, hubServer, dcName, taskCh)
 }
 
-func taskMetering(t *task.Tasker, dcName, namespace, server, wsEndpoint string) {
+func taskMetering(engine *metering.Engine, outbox *Outbox, dcName, namespace string) {
 	once := &sync.Once{}
+	last := time.Now()
 	tick := time.Tick(30 * time.Second)
-	for range tick {
-		metering, err := t.Metering()
-		if err != nil {
+	for now := range tick {
+		if err := engine.Sample(now); err != nil {
 			glog.Errorln("client fail to get metering:", err)
 			continue
 		}
 
-		if err := Broadcast(server, wsEndpoint, TendermintKey(dcName, namespace), metering); err != nil {
-			if strings.Contains(err.Error(), "Tx already exists in cache") {
-				glog.V(3).Infoln(err)
-			} else {
-				glog.Errorln("client fail to marshal metering:", err)
-			}
+		report := &metering.Report{
+			DataCenter: dcName,
+			Namespace:  namespace,
+			Time:       now,
+			Tasks:      engine.Tasks(),
+		}
+		start := last
+		last = now
+		if err := outbox.Push(start, now, report); err != nil {
+			glog.Errorln("client fail to queue metering:", err)
 			continue
 		}
 
@@ -97,6 +107,7 @@ func taskReciver(t *task.Tasker, hubServer, dcName string, taskCh chan<- *taskCt
 				closeStream()
 			} else {
 				redial = false
+				hubConnected.Set(1)
 			}
 
 			go startHeartBeatThread(t, dcName, stream, &redial)
@@ -104,11 +115,13 @@ func taskReciver(t *task.Tasker, hubServer, dcName string, taskCh chan<- *taskCt
 
 		if in, err := stream.Recv(); err == io.EOF {
 			redial = true
+			hubConnected.Set(0)
 			closeStream()
 			continue
 
 		} else if err != nil {
 			redial = true
+			hubConnected.Set(0)
 			closeStream()
 			time.Sleep(5 * time.Second)
 			glog.Errorln("Failed to receive task:", err)
@@ -146,6 +159,7 @@ func taskOperator(t *task.Tasker, dcName string, taskCh <-chan *taskCtx) {
 			return
 		}
 		modTimestamp = uint64(time.Now().UnixNano())
+		start := time.Now()
 
 		task := chTask.GetTask()
 		if task.GetTypeDeployment() == nil && task.GetTypeJob() == nil && task.GetTypeCronJob() == nil {
@@ -155,25 +169,26 @@ func taskOperator(t *task.Tasker, dcName string, taskCh <-chan *taskCtx) {
 		task.DataCenterName = dataCenterName
 
 		var (
-			deployment = task.GetTypeDeployment()
-			job        = task.GetTypeJob()
-			cronjob    = task.GetTypeCronJob()
-			attr       = task.GetAttributes()
-			err        error
+			cronjob = task.GetTypeCronJob()
+			attr    = task.GetAttributes()
+			spec    *types.HubTask
+			err     error
 		)
 
 		switch chTask.OpType {
 		case common_proto.DCOperation_TASK_CREATE:
-			switch task.Type {
-			case common_proto.TaskType_DEPLOYMENT:
-				err = t.CreateTasks(task.Id, strings.Split(deployment.Image, ",")...)
-			case common_proto.TaskType_JOB:
-				err = t.CreateJobs(task.Id, "", job.Image)
-			case common_proto.TaskType_CRONJOB:
-				err = t.CreateJobs(task.Id, cronjob.Schedule, cronjob.Image)
-			default:
-				err = errors.Errorf("INVALID TASK TYPE: %s", task.Type)
-				glog.Errorln(err)
+			if spec, err = hubTask(task); err == nil {
+				switch task.Type {
+				case common_proto.TaskType_DEPLOYMENT:
+					err = t.CreateTasks(task.Id, spec.Options, spec.Images...)
+				case common_proto.TaskType_JOB:
+					err = t.CreateJobs(task.Id, "", spec.Options, spec.Images...)
+				case common_proto.TaskType_CRONJOB:
+					err = t.CreateJobs(task.Id, cronjob.Schedule, spec.Options, spec.Images...)
+				default:
+					err = errors.Errorf("INVALID TASK TYPE: %s", task.Type)
+					glog.Errorln(err)
+				}
 			}
 			if err != nil {
 				task.Status = common_proto.TaskStatus_START_FAILED
@@ -183,16 +198,22 @@ func taskOperator(t *task.Tasker, dcName string, taskCh <-chan *taskCtx) {
 			}
 
 		case common_proto.DCOperation_TASK_UPDATE:
-			switch task.Type {
-			case common_proto.TaskType_DEPLOYMENT:
-				err = t.UpdateTask(task.Id, deployment.Image, uint32(attr.Replica), 80, 80)
-			case common_proto.TaskType_JOB:
-				err = t.CreateJobs(task.Id, "", job.Image)
-			case common_proto.TaskType_CRONJOB:
-				err = t.CreateJobs(task.Id, cronjob.Schedule, cronjob.Image)
-			default:
-				err = errors.Errorf("INVALID TASK TYPE: %s", task.Type)
-				glog.Errorln(err)
+			if spec, err = hubTask(task); err == nil {
+				switch task.Type {
+				case common_proto.TaskType_DEPLOYMENT:
+					if spec.Canary != nil {
+						err = canary(t, task.Id, spec)
+					} else {
+						err = t.UpdateTask(task.Id, spec.Images, uint32(attr.Replica), 80, 80, spec.Options)
+					}
+				case common_proto.TaskType_JOB:
+					err = t.CreateJobs(task.Id, "", spec.Options, spec.Images...)
+				case common_proto.TaskType_CRONJOB:
+					err = t.CreateJobs(task.Id, cronjob.Schedule, spec.Options, spec.Images...)
+				default:
+					err = errors.Errorf("INVALID TASK TYPE: %s", task.Type)
+					glog.Errorln(err)
+				}
 			}
 			if err != nil {
 				glog.V(1).Infoln(err)
@@ -221,15 +242,103 @@ func taskOperator(t *task.Tasker, dcName string, taskCh <-chan *taskCtx) {
 			}
 
 		}
+		taskOperations.WithLabelValues(chTask.OpType.String(), task.Type.String(), resultLabel(err)).
+			Observe(time.Since(start).Seconds())
 
 		report := ""
 		if err != nil {
 			report = err.Error()
+		} else if task.Status == common_proto.TaskStatus_UPDATE_SUCCESS && task.Type == common_proto.TaskType_DEPLOYMENT {
+			// report the endpoints assigned to the exposes
+			if endpoints, err := t.Endpoints(task.Id); err != nil {
+				glog.V(1).Infoln(err)
+			} else {
+				report = strings.Join(endpoints, ",")
+			}
 		}
 		chTask.DCStream.OpPayload = &common_proto.DCStream_TaskReport{
 			TaskReport: &common_proto.TaskReport{Task: task, Report: report}}
 		send(chTask.stream, chTask.DCStream)
+
+		// the rollout is followed apart, the operations of the other tasks
+		// are not held up by it
+		if err == nil && task.Type == common_proto.TaskType_DEPLOYMENT && t.ReadyTimeout() > 0 {
+			switch task.Status {
+			case common_proto.TaskStatus_START_SUCCESS:
+				go reportReady(t, chTask.stream, chTask.OpType, task, common_proto.TaskStatus_START_FAILED)
+			case common_proto.TaskStatus_UPDATE_SUCCESS:
+				go reportReady(t, chTask.stream, chTask.OpType, task, common_proto.TaskStatus_UPDATE_FAILED)
+			}
+		}
+	}
+}
+
+// hubTask is the spec of the task carried in the image of its type, with
+// the options of its order
+func hubTask(task *common_proto.Task) (*types.HubTask, error) {
+	image := ""
+	switch {
+	case task.GetTypeDeployment() != nil:
+		image = task.GetTypeDeployment().Image
+	case task.GetTypeJob() != nil:
+		image = task.GetTypeJob().Image
+	case task.GetTypeCronJob() != nil:
+		image = task.GetTypeCronJob().Image
+	}
+
+	spec, err := types.ParseHubTask(image)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Options, err = dcOrders.Apply(spec.Order, spec.Options); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// canary deploys, promotes or aborts the canary of the deployment task
+func canary(t *task.Tasker, name string, spec *types.HubTask) error {
+	switch spec.Canary.Action {
+	case types.CanaryPromote:
+		return t.Promote(name)
+	case types.CanaryAbort:
+		return t.AbortCanary(name)
+	default:
+		return t.Canary(name, strings.Join(spec.Images, ","), spec.Canary.Weight, spec.Options)
+	}
+}
+
+// reportReady reports the task again once its deployments are ready, or
+// with the failed status if they are not ready in the ready timeout
+func reportReady(t *task.Tasker, stream grpc_dcmgr.DCStreamer_ServerStreamClient, op common_proto.DCOperation,
+	task *common_proto.Task, failed common_proto.TaskStatus) {
+	report := "ready"
+	if err := t.WaitReady(task.Id); err != nil {
+		glog.V(1).Infoln(err)
+		task.Status = failed
+		report = err.Error()
 	}
+	send(stream, &common_proto.DCStream{
+		OpType: op,
+		OpPayload: &common_proto.DCStream_TaskReport{
+			TaskReport: &common_proto.TaskReport{Task: task, Report: report}},
+	})
+}
+
+// heartbeatMetrics are the metrics of the heartbeat, the inventory besides
+// the totals
+type heartbeatMetrics struct {
+	*task.Metrics
+	Inventory *types.ProviderInventoryStatus `json:",omitempty"`
+}
+
+// heartbeatSource is the inventory, whose listing is shared by its status
+// and the heartbeats within its max age, or the tasker
+func heartbeatSource(t *task.Tasker) inventory.Source {
+	if dcInventory != nil {
+		return dcInventory
+	}
+	return t
 }
 
 func heartBeat(t *task.Tasker, dcName string, stream grpc_dcmgr.DCStreamer_ServerStreamClient) error {
@@ -251,11 +360,19 @@ func heartBeat(t *task.Tasker, dcName string, stream grpc_dcmgr.DCStreamer_Serve
 		},
 	}
 
-	if metrics, err := t.Metrics(); err != nil {
+	if metrics, err := heartbeatSource(t).Metrics(); err != nil {
 		glog.V(1).Infoln(err)
 	} else {
 		metrics.TotalCPU = metrics.TotalCPU / 1000
-		data, _ := json.Marshal(metrics)
+		// the breakdown is served on /metrics, the heartbeat keeps the totals
+		metrics.Nodes, metrics.Tasks = nil, nil
+		report := &heartbeatMetrics{Metrics: metrics}
+		if dcInventory != nil {
+			if report.Inventory, err = dcInventory.Status(); err != nil {
+				glog.V(1).Infoln(err)
+			}
+		}
+		data, _ := json.Marshal(report)
 		message.DataCenter.DcHeartbeatReport.Metrics = string(data)
 	}
 
@@ -266,10 +383,12 @@ func heartBeat(t *task.Tasker, dcName string, stream grpc_dcmgr.DCStreamer_Serve
 		message.DataCenter.DcHeartbeatReport.Report = "deployment count : " + strconv.Itoa(len(tasks))
 	}
 
-	return send(stream, &common_proto.DCStream{
+	err := send(stream, &common_proto.DCStream{
 		OpType:    common_proto.DCOperation_HEARTBEAT,
 		OpPayload: &message,
 	})
+	hubHeartbeats.WithLabelValues(resultLabel(err)).Inc()
+	return err
 }
 
 func dialStream(timeout time.Duration, hubServer string) (grpc_dcmgr.DCStreamer_ServerStreamClient, func(), error) {
@@ -308,7 +427,13 @@ func dialStream(timeout time.Duration, hubServer string) (grpc_dcmgr.DCStreamer_
 	}, nil
 }
 
+// sendLock serializes the sends of the operator, the heartbeat and the
+// readiness reports on the stream
+var sendLock sync.Mutex
+
 func send(stream grpc_dcmgr.DCStreamer_ServerStreamClient, msg *common_proto.DCStream) error {
+	sendLock.Lock()
+	defer sendLock.Unlock()
 	if err := stream.Send(msg); err != nil {
 		glog.V(2).Infof("send (%v) fail: %s", *msg, err)
 		return err
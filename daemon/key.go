@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+type keyFile struct {
+	Address base.Bytes `json:"address"`
+	PubKey  base.Bytes `json:"pubKey"`
+	PrivKey base.Bytes `json:"privKey"`
+}
+
+// GenerateKey writes a new ed25519 key into the file, which must not exist
+func GenerateKey(path string) (ed25519.PrivKeyEd25519, error) {
+	key := ed25519.GenPrivKey()
+	pub := key.PubKey().(ed25519.PubKeyEd25519)
+	data, err := json.MarshalIndent(&keyFile{
+		Address: types.Address(pub),
+		PubKey:  pub[:],
+		PrivKey: key[:],
+	}, "", "    ")
+	if err != nil {
+		return key, errors.Wrap(err, "generate key")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return key, errors.Wrap(err, "generate key")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return key, errors.Wrap(err, "generate key")
+	}
+	defer file.Close()
+	_, err = file.Write(data)
+	return key, errors.Wrap(err, "generate key")
+}
+
+// LoadKey loads the ed25519 key of the file, the provider or an admin key
+func LoadKey(path string) (ed25519.PrivKeyEd25519, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ed25519.PrivKeyEd25519{}, errors.Wrap(err, "load key")
+	}
+	return decodeKey(data)
+}
+
+func decodeKey(data []byte) (ed25519.PrivKeyEd25519, error) {
+	var key ed25519.PrivKeyEd25519
+	file := &keyFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return key, errors.Wrap(err, "load key")
+	}
+	if len(file.PrivKey) != len(key) {
+		return key, errors.Errorf("key of %d bytes", len(file.PrivKey))
+	}
+	copy(key[:], file.PrivKey)
+	return key, nil
+}
@@ -0,0 +1,35 @@
+package daemon_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ankr-network/dccn-daemon/daemon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "provider_key.json")
+	_, err = daemon.LoadKey(path)
+	assert.Error(t, err)
+
+	key, err := daemon.GenerateKey(path)
+	require.NoError(t, err)
+	loaded, err := daemon.LoadKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	// the key is never replaced
+	_, err = daemon.GenerateKey(path)
+	assert.Error(t, err)
+	loaded, err = daemon.LoadKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+}
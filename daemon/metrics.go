@@ -0,0 +1,198 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "dccn"
+
+var (
+	hubConnected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "hub_connected",
+		Help:      "Whether the task stream to the hub is connected.",
+	})
+	hubHeartbeats = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "hub_heartbeats_total",
+		Help:      "Heartbeats sent to the hub by result.",
+	}, []string{"result"})
+	taskOperations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "task_operation_duration_seconds",
+		Help:      "Latency of the task operations requested by the hub.",
+		Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"operation", "type", "result"})
+	tendermintBroadcasts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "tendermint_broadcasts_total",
+		Help:      "Txs broadcast to tendermint by result: committed, duplicate, rejected or failed.",
+	}, []string{"result"})
+	tendermintCommits = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "tendermint_commit_duration_seconds",
+		Help:      "Latency from the broadcast of a tx until it is committed.",
+		Buckets:   prometheus.ExponentialBuckets(.5, 2, 8),
+	})
+)
+
+func init() {
+	prometheus.MustRegister(hubConnected, hubHeartbeats, taskOperations, tendermintBroadcasts, tendermintCommits)
+}
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "success"
+}
+
+var (
+	dcCPUDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_cpu_millicores",
+		"CPU of the datacenter nodes by state: allocatable or used.", []string{"state"}, nil)
+	dcMemoryDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_memory_bytes",
+		"Memory of the datacenter nodes by state: allocatable or used.", []string{"state"}, nil)
+	dcStorageDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_ephemeral_storage_bytes",
+		"Ephemeral storage of the datacenter nodes by state: allocatable or used.", []string{"state"}, nil)
+	dcImagesDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_images",
+		"Images cached on the datacenter nodes.", nil, nil)
+	dcEndpointsDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_endpoints",
+		"Ports exposed by the task services.", nil, nil)
+	dcUpDesc = prometheus.NewDesc(metricsNamespace+"_datacenter_up",
+		"Whether the datacenter metrics are listed from kubernetes.", nil, nil)
+	taskReplicasDesc = prometheus.NewDesc(metricsNamespace+"_task_replicas",
+		"Replicas of the task deployments.", []string{"task"}, nil)
+	meteringCPUDesc = prometheus.NewDesc(metricsNamespace+"_metering_cpu_millicore_seconds_total",
+		"CPU metered for the tasks by kind: requested, limited or consumed.", []string{"task", "kind"}, nil)
+	meteringMemoryDesc = prometheus.NewDesc(metricsNamespace+"_metering_memory_byte_seconds_total",
+		"Memory metered for the tasks by kind: requested, limited or consumed.", []string{"task", "kind"}, nil)
+	meteringDiskDesc = prometheus.NewDesc(metricsNamespace+"_metering_disk_byte_seconds_total",
+		"Disk metered for the tasks by kind: requested or consumed.", []string{"task", "kind"}, nil)
+	nodeCPUDesc = prometheus.NewDesc(metricsNamespace+"_node_cpu_millicores",
+		"CPU of the nodes by state: allocatable, used, requested or schedulable.", []string{"node", "state"}, nil)
+	nodeMemoryDesc = prometheus.NewDesc(metricsNamespace+"_node_memory_bytes",
+		"Memory of the nodes by state: allocatable, used, requested or schedulable.", []string{"node", "state"}, nil)
+	nodePodsDesc = prometheus.NewDesc(metricsNamespace+"_node_pods",
+		"Pods of the nodes by state: scheduled or capacity.", []string{"node", "state"}, nil)
+	nodeNetworkDesc = prometheus.NewDesc(metricsNamespace+"_node_network_bytes_total",
+		"Traffic of the nodes by direction: rx or tx.", []string{"node", "direction"}, nil)
+	taskPodsDesc = prometheus.NewDesc(metricsNamespace+"_task_pods",
+		"Running pods of the tasks.", []string{"task"}, nil)
+	taskNetworkDesc = prometheus.NewDesc(metricsNamespace+"_task_network_bytes_total",
+		"Traffic of the running pods of the tasks by direction: rx or tx.", []string{"task", "direction"}, nil)
+	outboxDesc = prometheus.NewDesc(metricsNamespace+"_outbox_records",
+		"Records queued in the outbox and not committed to tendermint.", nil, nil)
+)
+
+// collector lists the datacenter, the tasks and the metering on scrape, from
+// the inventory if any, which lists them once per its max age. The usage of a
+// task is the rate of its metering totals.
+type collector struct {
+	source inventory.Source
+	engine *metering.Engine
+	outbox *Outbox
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		dcCPUDesc, dcMemoryDesc, dcStorageDesc, dcImagesDesc, dcEndpointsDesc, dcUpDesc,
+		nodeCPUDesc, nodeMemoryDesc, nodePodsDesc, nodeNetworkDesc, taskPodsDesc, taskNetworkDesc,
+		taskReplicasDesc, meteringCPUDesc, meteringMemoryDesc, meteringDiskDesc, outboxDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+	counter := func(desc *prometheus.Desc, value uint64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), labels...)
+	}
+
+	if metrics, err := c.source.Metrics(); err != nil {
+		glog.V(1).Infoln("collect datacenter metrics:", err)
+		gauge(dcUpDesc, 0)
+	} else {
+		gauge(dcUpDesc, 1)
+		gauge(dcCPUDesc, float64(metrics.TotalCPU), "allocatable")
+		gauge(dcCPUDesc, float64(metrics.UsedCPU), "used")
+		gauge(dcMemoryDesc, float64(metrics.TotalMemory), "allocatable")
+		gauge(dcMemoryDesc, float64(metrics.UsedMemory), "used")
+		gauge(dcStorageDesc, float64(metrics.TotalStorage), "allocatable")
+		gauge(dcStorageDesc, float64(metrics.UsedStorage), "used")
+		gauge(dcImagesDesc, float64(metrics.ImageCount))
+		gauge(dcEndpointsDesc, float64(metrics.EndPointCount))
+		for name, node := range metrics.Nodes {
+			gauge(nodeCPUDesc, float64(node.Allocatable.CPU), name, "allocatable")
+			gauge(nodeCPUDesc, float64(node.Used.CPU), name, "used")
+			gauge(nodeCPUDesc, float64(node.Requested.CPU), name, "requested")
+			gauge(nodeCPUDesc, float64(node.Schedulable.CPU), name, "schedulable")
+			gauge(nodeMemoryDesc, float64(node.Allocatable.Memory), name, "allocatable")
+			gauge(nodeMemoryDesc, float64(node.Used.Memory), name, "used")
+			gauge(nodeMemoryDesc, float64(node.Requested.Memory), name, "requested")
+			gauge(nodeMemoryDesc, float64(node.Schedulable.Memory), name, "schedulable")
+			gauge(nodePodsDesc, float64(node.Pods), name, "scheduled")
+			gauge(nodePodsDesc, float64(node.PodCapacity), name, "capacity")
+			counter(nodeNetworkDesc, uint64(node.Network.RxBytes), name, "rx")
+			counter(nodeNetworkDesc, uint64(node.Network.TxBytes), name, "tx")
+		}
+		for name, usage := range metrics.Tasks {
+			gauge(taskReplicasDesc, float64(usage.Replicas), name)
+			gauge(taskPodsDesc, float64(usage.Pods), name)
+			counter(taskNetworkDesc, uint64(usage.Network.RxBytes), name, "rx")
+			counter(taskNetworkDesc, uint64(usage.Network.TxBytes), name, "tx")
+		}
+	}
+
+	if c.engine != nil {
+		for name, record := range c.engine.Tasks() {
+			counter(meteringCPUDesc, record.Requested.CPU, name, "requested")
+			counter(meteringCPUDesc, record.Limited.CPU, name, "limited")
+			counter(meteringCPUDesc, record.Consumed.CPU, name, "consumed")
+			counter(meteringMemoryDesc, record.Requested.Memory, name, "requested")
+			counter(meteringMemoryDesc, record.Limited.Memory, name, "limited")
+			counter(meteringMemoryDesc, record.Consumed.Memory, name, "consumed")
+			counter(meteringDiskDesc, record.Requested.Disk, name, "requested")
+			counter(meteringDiskDesc, record.Consumed.Disk, name, "consumed")
+		}
+	}
+
+	if c.outbox != nil {
+		gauge(outboxDesc, float64(c.outbox.Len()))
+	}
+}
+
+// ServeMetrics serves the prometheus metrics of the datacenter, the tasks
+// and the daemon on /metrics of the addr, the inventory may be nil
+func ServeMetrics(addr string, tasker *task.Tasker, inv *inventory.Inventory, engine *metering.Engine, outbox *Outbox) error {
+	var source inventory.Source = tasker
+	if inv != nil {
+		source = inv
+	}
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(&collector{source: source, engine: engine, outbox: outbox}); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, registry},
+		promhttp.HandlerOpts{ErrorLog: glogger{}}))
+	glog.Infoln("Metrics server started on", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// glogger logs the errors of the metrics handler
+type glogger struct{}
+
+func (glogger) Println(v ...interface{}) {
+	glog.Errorln(v...)
+}
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"github.com/Ankr-network/dccn-daemon/bidengine"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+)
+
+// Orders looks up the orders of the hub tasks, the deployment groups won by
+// the provider
+type Orders struct {
+	source   bidengine.Source
+	provider base.Bytes
+}
+
+// NewOrders looks up the orders in the source, the chain or the orders file
+// of the bid engine
+func NewOrders(source bidengine.Source, provider base.Bytes) *Orders {
+	return &Orders{source: source, provider: provider}
+}
+
+// Apply sets the options of the task from the deployment group of its order,
+// <deployment>/<seq>, the requirements and the resources ordered are not
+// chosen by the tenant. A task without order has neither, and does not
+// autoscale as its replicas are not bounded.
+func (o *Orders) Apply(id string, options *types.ServiceOptions) (*types.ServiceOptions, error) {
+	res := &types.ServiceOptions{}
+	if options != nil {
+		*res = *options
+	}
+	res.Requirements, res.Order, res.OrderID = nil, nil, ""
+	if id == "" {
+		if res.Autoscale != nil {
+			return nil, errors.New("autoscale without order")
+		}
+		return res, nil
+	}
+	if o == nil {
+		return nil, errors.Errorf("order %s: no order source", id)
+	}
+
+	group, err := types.ParseDeploymentGroupID(id)
+	if err != nil {
+		return nil, err
+	}
+	order, err := o.source.Order(group)
+	if err != nil {
+		return nil, err
+	}
+	if order.Group.State != types.DeploymentGroup_ORDERED || !order.Winner.Equal(o.provider) {
+		return nil, errors.Errorf("order %s not won by the provider", id)
+	}
+
+	res.Requirements = order.Group.Requirements
+	res.Order = order.Group.Resources
+	res.OrderID = id
+	return res, nil
+}
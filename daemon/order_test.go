@@ -0,0 +1,71 @@
+package daemon_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ankr-network/dccn-daemon/bidengine"
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/daemon"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrders_Apply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orders")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	provider := base.Bytes{1}
+	requirements := []types.ProviderAttribute{{Name: "region", Value: "us-west"}}
+	resources := []types.ResourceGroup{{Unit: types.ResourceUnit{CPU: 500}, Count: 2}}
+	group := func(seq uint64, state types.DeploymentGroup_DeploymentGroupState) types.DeploymentGroup {
+		return types.DeploymentGroup{
+			DeploymentGroupID: types.DeploymentGroupID{Deployment: base.Bytes{0xab}, Seq: seq},
+			State:             state,
+			Requirements:      requirements,
+			Resources:         resources,
+		}
+	}
+	data, err := json.Marshal([]*chain.Order{
+		{Group: group(1, types.DeploymentGroup_ORDERED), Winner: provider},
+		{Group: group(2, types.DeploymentGroup_ORDERED), Winner: base.Bytes{2}},
+		{Group: group(3, types.DeploymentGroup_OPEN)},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(dir, "orders.json")
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+	orders := daemon.NewOrders(bidengine.NewFileSource(path, provider), provider)
+
+	// the requirements and the order of the tenant are replaced by those of
+	// the group
+	tenant := &types.ServiceOptions{
+		Requirements: []types.ProviderAttribute{{Name: "pool", Value: "gpu"}},
+		Order:        []types.ResourceGroup{{Unit: types.ResourceUnit{CPU: 500}, Count: 10}},
+	}
+	options, err := orders.Apply("ab/1", tenant)
+	assert.NoError(t, err)
+	assert.Equal(t, requirements, options.Requirements)
+	assert.Equal(t, resources, options.Order)
+	assert.Equal(t, "ab/1", options.OrderID)
+
+	options, err = orders.Apply("", tenant)
+	assert.NoError(t, err)
+	assert.Empty(t, options.Requirements)
+	assert.Empty(t, options.Order)
+
+	// the autoscaling is bounded by the order
+	_, err = orders.Apply("", &types.ServiceOptions{Autoscale: &types.AutoscaleOptions{Min: 1, Max: 10, CPU: 80}})
+	assert.Error(t, err)
+
+	// won by another provider, open, unknown
+	for _, id := range []string{"ab/2", "ab/3", "ab/4", "ab"} {
+		_, err = orders.Apply(id, tenant)
+		assert.Error(t, err, id)
+	}
+}
@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"context"
+	"net"
+
+	"github.com/Ankr-network/dccn-daemon/bidengine"
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusServer serves the status of the provider and of its tasks, the tasks
+// are deployed through the hub only
+type StatusServer struct {
+	tasker    *task.Tasker
+	inventory *inventory.Inventory
+	bids      *bidengine.Engine
+	provider  base.Bytes
+	version   *types.Version
+}
+
+// NewStatusServer creates the status server, bids is nil if the bid engine
+// is disabled
+func NewStatusServer(tasker *task.Tasker, inv *inventory.Inventory, bids *bidengine.Engine,
+	provider base.Bytes, version *types.Version) *StatusServer {
+	return &StatusServer{
+		tasker:    tasker,
+		inventory: inv,
+		bids:      bids,
+		provider:  provider,
+		version:   version,
+	}
+}
+
+// Status reports the inventory of the datacenter, the error is in the
+// code and the message
+func (s *StatusServer) Status(ctx context.Context, req *types.Empty) (*types.ServerStatus, error) {
+	res := &types.ServerStatus{
+		Provider: s.provider,
+		Version:  s.version,
+		Status: &types.ProviderStatus{
+			Cluster:   &types.ProviderClusterStatus{},
+			Manifest:  &types.ProviderManifestStatus{},
+			Bidengine: &types.ProviderBidengineStatus{},
+		},
+	}
+	if s.bids != nil {
+		res.Status.Bidengine.Orders = uint32(s.bids.Orders())
+	}
+
+	inv, err := s.inventory.Status()
+	if err != nil {
+		res.Code, res.Message = int32(codes.Unavailable), err.Error()
+		return res, nil
+	}
+	res.Status.Cluster.Inventory = inv
+	res.Status.Manifest.Deployments = uint32(s.inventory.Deployments())
+	return res, nil
+}
+
+func (s *StatusServer) Deploy(ctx context.Context, req *types.ManifestRequest) (*types.DeployRespone, error) {
+	return nil, status.Error(codes.Unimplemented, "tasks are deployed through the hub")
+}
+
+func (s *StatusServer) ServiceStatus(ctx context.Context, req *types.ServiceStatusRequest) (*types.ServiceStatusResponse, error) {
+	res, err := s.tasker.ServiceStatus(req.Name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return res, nil
+}
+
+func (s *StatusServer) ServiceLogs(req *types.LogRequest, stream types.Cluster_ServiceLogsServer) error {
+	return status.Error(codes.Unimplemented, "service logs not supported")
+}
+
+// ServeStatus serves the status server on the grpc addr
+func ServeStatus(addr string, server *StatusServer) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrap(err, "listen status")
+	}
+
+	s := grpc.NewServer()
+	types.RegisterClusterServer(s, server)
+	glog.Infoln("Status server started on", addr)
+	return s.Serve(lis)
+}
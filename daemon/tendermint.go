@@ -2,22 +2,361 @@ package daemon
 
 import (
 	"encoding/json"
-	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
-	"github.com/tendermint/tendermint/rpc/client"
-	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
-// Broadcast will marshal and push the obj to the tendermint block
-func Broadcast(server, wsEndpoint, key string, obj interface{}) error {
+const (
+	outboxBatch    = 20
+	minBackoff     = time.Second
+	maxBackoff     = 5 * time.Minute
+	confirmTimeout = 30 * time.Second
+	confirmPoll    = time.Second
+)
+
+// Chain is the tendermint rpc used by the outbox, implemented by the
+// client.HTTP
+type Chain interface {
+	ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error)
+	BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error)
+	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
+}
+
+type outboxRecord struct {
+	Seq   uint64          `json:"seq"`
+	Start time.Time       `json:"start"`
+	End   time.Time       `json:"end"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type outboxFile struct {
+	Seq      uint64         `json:"seq"`
+	Records  []outboxRecord `json:"records"`
+	Inflight int            `json:"inflight"`
+}
+
+// Outbox queues the records of a datacenter namespace until tendermint
+// commits them. The queue is saved into the file, if any, so the records
+// survive the restarts and the chain outages.
+type Outbox struct {
+	sync.Mutex
+	path   string
+	txType types.TxEnvelope_Type
+	dcName string
+	ns     string
+	key    ed25519.PrivKeyEd25519
+	chain  Chain
+
+	seq uint64
+	// the sequences follow the ones committed, false until the chain is
+	// queried when the outbox starts without its file
+	seeded  bool
+	records []outboxRecord
+	// records of the batch sent but not confirmed, resent as the same tx
+	inflight int
+	wake     chan struct{}
+}
+
+// NewOutbox loads the records left in the file, the path may be empty to
+// only queue in memory. The records are sent as the payloads of txs of the
+// type, signed by the provider key. Without the file, the sequences follow
+// the last one the chain committed.
+func NewOutbox(path string, txType types.TxEnvelope_Type, dcName, namespace string,
+	key ed25519.PrivKeyEd25519, chain Chain) (*Outbox, error) {
+	o := &Outbox{
+		path:   path,
+		txType: txType,
+		dcName: dcName,
+		ns:     namespace,
+		key:    key,
+		chain:  chain,
+		wake:   make(chan struct{}, 1),
+	}
+	if path == "" {
+		return o, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return o, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "load outbox")
+	}
+
+	file := &outboxFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, errors.Wrap(err, "load outbox")
+	}
+	o.seq, o.records, o.inflight = file.Seq, file.Records, file.Inflight
+	o.seeded = true
+	return o, nil
+}
+
+// Push marshals and queues the obj of the period
+func (o *Outbox) Push(start, end time.Time, obj interface{}) error {
 	data, err := json.Marshal(obj)
 	if err != nil {
 		return errors.Wrap(err, "json marshal")
 	}
 
-	c := client.NewHTTP(server, wsEndpoint)
-	_, err = c.BroadcastTxCommit(types.Tx(
-		fmt.Sprintf("%s=%s", key, data)))
-	return errors.Wrap(err, "broadcast")
+	o.Lock()
+	defer o.Unlock()
+	o.seq++
+	o.records = append(o.records, outboxRecord{Seq: o.seq, Start: start, End: end, Data: data})
+	if err := o.save(); err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of the records not committed
+func (o *Outbox) Len() int {
+	o.Lock()
+	defer o.Unlock()
+	return len(o.records)
+}
+
+// Run sends the records in batches until stop is closed, retrying with
+// exponential backoff
+func (o *Outbox) Run(stop <-chan struct{}) {
+	backoff := minBackoff
+	for {
+		wake := o.wake
+		var wait <-chan time.Time
+		switch sent, err := o.flush(); {
+		case err != nil:
+			glog.Errorf("outbox fail to send %d records, retry in %v: %v", o.Len(), backoff, err)
+			// the records pushed meanwhile do not cut the backoff short
+			wake, wait = nil, time.After(backoff)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		case sent:
+			backoff = minBackoff
+			continue
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-wake:
+		case <-wait:
+		}
+	}
+}
+
+// Flush sends the records once, without retrying
+func (o *Outbox) Flush() error {
+	for {
+		sent, err := o.flush()
+		if err != nil || !sent {
+			return err
+		}
+	}
+}
+
+// flush sends the first batch and waits it committed
+func (o *Outbox) flush() (sent bool, err error) {
+	if !o.seeded {
+		if err := o.seed(); err != nil {
+			return false, err
+		}
+	}
+
+	o.Lock()
+	if o.inflight == 0 {
+		o.inflight = len(o.records)
+		if o.inflight > outboxBatch {
+			o.inflight = outboxBatch
+		}
+		if err := o.save(); err != nil {
+			o.Unlock()
+			return false, err
+		}
+	}
+	records := o.records[:o.inflight]
+	o.Unlock()
+	if len(records) == 0 {
+		return false, nil
+	}
+
+	tx, err := o.encode(records)
+	if err != nil {
+		return false, err
+	}
+
+	begin := time.Now()
+	var result *ctypes.ResultTx
+	res, err := o.chain.BroadcastTxSync(tx)
+	switch {
+	case err != nil:
+		// a batch sent before a failure or a restart may be committed
+		// already, or still in the mempool cache
+		tendermintBroadcasts.WithLabelValues("failed").Inc()
+		var cerr error
+		if result, cerr = o.confirm(tx.Hash(), 0); cerr != nil {
+			return false, errors.Wrap(err, "broadcast")
+		}
+	case res.Code == chain.CodeDuplicate:
+		// the sequence is committed, by a send of the batch before
+		glog.V(1).Infof("outbox batch from sequence %d is committed: %s", records[0].Seq, res.Log)
+		tendermintBroadcasts.WithLabelValues("duplicate").Inc()
+		return true, o.drop(len(records))
+	case res.Code != 0:
+		tendermintBroadcasts.WithLabelValues("rejected").Inc()
+		return true, o.reject(records, errors.Errorf("check tx code %d: %s", res.Code, res.Log))
+	default:
+		if result, err = o.confirm(tx.Hash(), confirmTimeout); err != nil {
+			return false, err
+		}
+	}
+
+	// the tx in a block is refused by the deliver tx, as by the check tx
+	switch code := result.TxResult.Code; {
+	case code == chain.CodeDuplicate:
+		glog.V(1).Infof("outbox batch from sequence %d is committed: %s", records[0].Seq, result.TxResult.Log)
+		tendermintBroadcasts.WithLabelValues("duplicate").Inc()
+		return true, o.drop(len(records))
+	case code != 0:
+		tendermintBroadcasts.WithLabelValues("rejected").Inc()
+		return true, o.reject(records, errors.Errorf("deliver tx code %d: %s", code, result.TxResult.Log))
+	}
+	tendermintBroadcasts.WithLabelValues("committed").Inc()
+	tendermintCommits.Observe(time.Since(begin).Seconds())
+	return true, o.drop(len(records))
+}
+
+// seed numbers the records queued after the last sequence committed
+func (o *Outbox) seed() error {
+	res, err := o.chain.ABCIQuery(chain.QuerySeq, cmn.HexBytes(TendermintKey(o.dcName, o.ns)))
+	if err != nil {
+		return errors.Wrap(err, "query sequence")
+	}
+	if res.Response.Code != chain.CodeOK {
+		return errors.Errorf("query sequence code %d: %s", res.Response.Code, res.Response.Log)
+	}
+	var last uint64
+	if err := json.Unmarshal(res.Response.Value, &last); err != nil {
+		return errors.Wrap(err, "query sequence")
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	for i := range o.records {
+		o.records[i].Seq = last + uint64(i) + 1
+	}
+	o.seq = last + uint64(len(o.records))
+	o.seeded = true
+	return o.save()
+}
+
+// drop removes the first n records, sent in the inflight batch
+func (o *Outbox) drop(n int) error {
+	o.Lock()
+	defer o.Unlock()
+	o.records = o.records[n:]
+	o.inflight = 0
+	return o.save()
+}
+
+// reject moves the batch the chain refuses for good aside, into the rejected
+// file beside the outbox, so the records behind it are sent
+func (o *Outbox) reject(records []outboxRecord, reason error) error {
+	glog.Errorf("outbox drops %d records from sequence %d: %v", len(records), records[0].Seq, reason)
+	if o.path != "" {
+		file, err := os.OpenFile(o.path+".rejected", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return errors.Wrap(err, "reject outbox records")
+		}
+		defer file.Close()
+
+		enc := json.NewEncoder(file)
+		for i := range records {
+			if err := enc.Encode(&records[i]); err != nil {
+				return errors.Wrap(err, "reject outbox records")
+			}
+		}
+	}
+	return o.drop(len(records))
+}
+
+// encode signs the records as the payload of a tx, the sequence of the first
+// record is the sequence of the tx
+func (o *Outbox) encode(records []outboxRecord) (tmtypes.Tx, error) {
+	payload := make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		payload = append(payload, record.Data)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "json marshal")
+	}
+
+	envelope := &types.TxEnvelope{
+		Type:       o.txType,
+		Version:    types.TxVersion,
+		DataCenter: o.dcName,
+		Namespace:  o.ns,
+		Seq:        records[0].Seq,
+		Payload:    data,
+	}
+	envelope.SetPeriod(records[0].Start, records[len(records)-1].End)
+	if err := envelope.Sign(o.key); err != nil {
+		return nil, err
+	}
+	data, err = types.EncodeTx(envelope)
+	return tmtypes.Tx(data), err
+}
+
+// confirm polls the tx until committed or the timeout, the result holds the
+// code of its deliver tx
+func (o *Outbox) confirm(hash []byte, timeout time.Duration) (*ctypes.ResultTx, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		res, err := o.chain.Tx(hash, false)
+		if err == nil {
+			return res, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, errors.Wrapf(err, "confirm tx %X", hash)
+		}
+		time.Sleep(confirmPoll)
+	}
+}
+
+// save writes the queue into the file, replaced atomically
+func (o *Outbox) save() error {
+	if o.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(&outboxFile{Seq: o.seq, Records: o.records, Inflight: o.inflight})
+	if err != nil {
+		return errors.Wrap(err, "save outbox")
+	}
+	if err := os.MkdirAll(filepath.Dir(o.path), 0700); err != nil {
+		return errors.Wrap(err, "save outbox")
+	}
+	tmp := o.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrap(err, "save outbox")
+	}
+	return errors.Wrap(os.Rename(tmp, o.path), "save outbox")
 }
@@ -0,0 +1,232 @@
+package daemon_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/chain"
+	"github.com/Ankr-network/dccn-daemon/daemon"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// fakeChain commits the txs it accepts at once
+type fakeChain struct {
+	sync.Mutex
+	// broadcasts failing, before the chain is up
+	down int
+	// broadcasts committed whose response is lost
+	lost int
+	// check tx code of the broadcasts, 0 to accept them
+	code uint32
+	// deliver tx code of the txs committed
+	deliver uint32
+	// last sequence committed before
+	last uint64
+
+	sent      []tmtypes.Tx
+	committed map[string]bool
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{committed: map[string]bool{}}
+}
+
+func (c *fakeChain) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if path != chain.QuerySeq || string(data) != "dc:default" {
+		return nil, errors.Errorf("unexpected query %s %s", path, data)
+	}
+	value, err := json.Marshal(c.last)
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: value}}, err
+}
+
+func (c *fakeChain) BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.sent = append(c.sent, tx)
+	switch {
+	case c.down > 0:
+		c.down--
+		return nil, errors.New("connection refused")
+	case c.code != 0:
+		return &ctypes.ResultBroadcastTx{Code: c.code, Log: "refused"}, nil
+	}
+	c.committed[string(tx.Hash())] = true
+	if c.lost > 0 {
+		c.lost--
+		return nil, errors.New("connection reset")
+	}
+	return &ctypes.ResultBroadcastTx{}, nil
+}
+
+func (c *fakeChain) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.committed[string(hash)] {
+		return nil, errors.New("tx not found")
+	}
+	return &ctypes.ResultTx{TxResult: abci.ResponseDeliverTx{Code: c.deliver, Log: "refused"}}, nil
+}
+
+// seqs returns the sequences of the txs sent
+func (c *fakeChain) seqs(t *testing.T) []uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	seqs := make([]uint64, 0, len(c.sent))
+	for _, tx := range c.sent {
+		envelope, err := types.DecodeTx(tx)
+		require.NoError(t, err)
+		seqs = append(seqs, envelope.Seq)
+	}
+	return seqs
+}
+
+func TestOutbox_Restart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "outbox.json")
+	key := ed25519.GenPrivKey()
+	now := time.Unix(1550000000, 0)
+
+	c := newFakeChain()
+	c.down = 1
+	outbox, err := daemon.NewOutbox(path, types.TxEnvelope_METERING, "dc", "default", key, c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	require.NoError(t, outbox.Push(now.Add(time.Minute), now.Add(2*time.Minute), "second"))
+	assert.Error(t, outbox.Flush())
+	require.NoError(t, outbox.Push(now.Add(2*time.Minute), now.Add(3*time.Minute), "third"))
+
+	// the batch in flight is resent as the same tx after a restart
+	outbox, err = daemon.NewOutbox(path, types.TxEnvelope_METERING, "dc", "default", key, c)
+	require.NoError(t, err)
+	assert.Equal(t, 3, outbox.Len())
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, 0, outbox.Len())
+	require.Len(t, c.sent, 3)
+	assert.Equal(t, c.sent[0], c.sent[1])
+	assert.Equal(t, []uint64{1, 1, 3}, c.seqs(t))
+}
+
+func TestOutbox_Seed(t *testing.T) {
+	now := time.Unix(1550000000, 0)
+
+	// the sequences of an outbox started without its file follow the chain
+	c := newFakeChain()
+	c.last = 5
+	outbox, err := daemon.NewOutbox("", types.TxEnvelope_METERING, "dc", "default", ed25519.GenPrivKey(), c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	require.NoError(t, outbox.Push(now.Add(time.Minute), now.Add(2*time.Minute), "second"))
+	require.NoError(t, outbox.Flush())
+	require.NoError(t, outbox.Push(now.Add(2*time.Minute), now.Add(3*time.Minute), "third"))
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, []uint64{6, 8}, c.seqs(t))
+}
+
+func TestOutbox_Committed(t *testing.T) {
+	key := ed25519.GenPrivKey()
+	now := time.Unix(1550000000, 0)
+
+	// the tx is committed but the response of the broadcast is lost
+	c := newFakeChain()
+	c.lost = 1
+	outbox, err := daemon.NewOutbox("", types.TxEnvelope_METERING, "dc", "default", key, c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, 0, outbox.Len())
+	assert.Len(t, c.sent, 1)
+
+	// the sequence is committed by a send before
+	c = newFakeChain()
+	c.code = chain.CodeDuplicate
+	outbox, err = daemon.NewOutbox("", types.TxEnvelope_METERING, "dc", "default", key, c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, 0, outbox.Len())
+}
+
+func TestOutbox_Rejected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "outbox.json")
+	now := time.Unix(1550000000, 0)
+	c := newFakeChain()
+	c.code = chain.CodeInvalid
+	outbox, err := daemon.NewOutbox(path, types.TxEnvelope_METERING, "dc", "default", ed25519.GenPrivKey(), c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, 0, outbox.Len())
+
+	data, err := ioutil.ReadFile(path + ".rejected")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"seq":1`)
+
+	// the tx is committed in a block but its deliver tx refuses it
+	c = newFakeChain()
+	c.deliver = chain.CodeInvalid
+	outbox, err = daemon.NewOutbox(path, types.TxEnvelope_METERING, "dc", "default", ed25519.GenPrivKey(), c)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "second"))
+	require.NoError(t, outbox.Flush())
+	assert.Equal(t, 0, outbox.Len())
+
+	data, err = ioutil.ReadFile(path + ".rejected")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"second"`)
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	now := time.Unix(1550000000, 0)
+	c := newFakeChain()
+	c.down = 1
+	outbox, err := daemon.NewOutbox("", types.TxEnvelope_METERING, "dc", "default", ed25519.GenPrivKey(), c)
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		outbox.Run(stop)
+		close(done)
+	}()
+	pushed := time.Now()
+	require.NoError(t, outbox.Push(now, now.Add(time.Minute), "first"))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, outbox.Push(now.Add(time.Minute), now.Add(2*time.Minute), "second"))
+
+	// retried once the backoff expired, not on the push
+	deadline := time.Now().Add(5 * time.Second)
+	for outbox.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	close(stop)
+	<-done
+	assert.Equal(t, 0, outbox.Len())
+	assert.True(t, time.Since(pushed) >= time.Second)
+	assert.Equal(t, []uint64{1, 1, 2}, c.seqs(t))
+}
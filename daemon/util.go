@@ -1,6 +1,8 @@
 package daemon
 
+import "github.com/Ankr-network/dccn-daemon/types"
+
 // TendermintKey will create the key to be used in the tendermint
 func TendermintKey(dcName, namespace string) string {
-	return dcName + ":" + namespace
+	return (&types.TxEnvelope{DataCenter: dcName, Namespace: namespace}).Key()
 }
@@ -0,0 +1,85 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+)
+
+// Forecast is the headroom of a resource, and when the requests of the pods
+// are projected to exhaust the capacity
+type Forecast struct {
+	Resource  string `json:"resource"`
+	Capacity  int64  `json:"capacity"`
+	Requested int64  `json:"requested"`
+	Used      int64  `json:"used"`
+	// Capacity less requested
+	Headroom int64 `json:"headroom"`
+	// Change of the requests per hour, the least squares fit of the history
+	Trend float64 `json:"trend"`
+	// Zero if the requests are not growing
+	Exhausted time.Time `json:"exhausted,omitempty"`
+}
+
+// Forecasts fits the requests of the samples, the last sample is the
+// current usage. No forecast without samples.
+func Forecasts(samples []Sample) []Forecast {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	resources := []struct {
+		name  string
+		value func(m *kube.Metric) int64
+	}{
+		{"cpu", func(m *kube.Metric) int64 { return m.CPU }},
+		{"memory", func(m *kube.Metric) int64 { return m.Memory }},
+		{"ephemeral-storage", func(m *kube.Metric) int64 { return m.EphemeralStorage }},
+	}
+
+	last := samples[len(samples)-1]
+	forecasts := make([]Forecast, 0, len(resources))
+	for _, resource := range resources {
+		forecast := Forecast{
+			Resource:  resource.name,
+			Capacity:  resource.value(&last.Capacity),
+			Requested: resource.value(&last.Requested),
+			Used:      resource.value(&last.Used),
+		}
+		forecast.Headroom = forecast.Capacity - forecast.Requested
+		if forecast.Headroom < 0 {
+			forecast.Headroom = 0
+		}
+
+		forecast.Trend = trend(samples, resource.value)
+		if forecast.Trend > 0 {
+			hours := float64(forecast.Headroom) / forecast.Trend
+			forecast.Exhausted = last.Time.Add(time.Duration(hours * float64(time.Hour)))
+		}
+		forecasts = append(forecasts, forecast)
+	}
+	return forecasts
+}
+
+// trend is the slope per hour of the least squares line of the requests
+func trend(samples []Sample, value func(m *kube.Metric) int64) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	start := samples[0].Time
+	var sumX, sumY, sumXX, sumXY float64
+	for i := range samples {
+		x := samples[i].Time.Sub(start).Hours()
+		y := float64(value(&samples[i].Requested))
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	n := float64(len(samples))
+	if d := n*sumXX - sumX*sumX; d != 0 {
+		return (n*sumXY - sumX*sumY) / d
+	}
+	return 0
+}
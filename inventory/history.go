@@ -0,0 +1,163 @@
+package inventory
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// sampleInterval is the least time between the samples of the history
+const sampleInterval = time.Minute
+
+// Sample is the capacity and the usage of the schedulable nodes at a time,
+// the capacity is overcommitted
+type Sample struct {
+	Time      time.Time   `json:"time"`
+	Capacity  kube.Metric `json:"capacity"`
+	Requested kube.Metric `json:"requested"`
+	Used      kube.Metric `json:"used"`
+}
+
+// history is the rolling window of the samples, appended to the file one
+// JSON sample per line
+type history struct {
+	path    string
+	window  time.Duration
+	samples []Sample
+	// samples in the file and out of the window
+	dropped int
+}
+
+// ReadHistory returns the samples of the file within the window before now,
+// all if window is 0. A truncated last line is ignored.
+func ReadHistory(path string, window time.Duration) ([]Sample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read history")
+	}
+	defer file.Close()
+
+	var samples []Sample
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return samples, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read history")
+		}
+
+		sample := Sample{}
+		if err := json.Unmarshal(line, &sample); err != nil {
+			return nil, errors.Wrap(err, "read history")
+		}
+		if window == 0 || time.Since(sample.Time) <= window {
+			samples = append(samples, sample)
+		}
+	}
+}
+
+// SetHistory keeps the samples of the window in the file, all if window is
+// 0, the samples left in the file are loaded
+func (inv *Inventory) SetHistory(path string, window time.Duration) error {
+	samples, err := ReadHistory(path, window)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return err
+	}
+
+	inv.Lock()
+	defer inv.Unlock()
+	inv.history = &history{path: path, window: window, samples: samples}
+	return inv.history.save()
+}
+
+// History returns the samples of the window
+func (inv *Inventory) History() []Sample {
+	inv.Lock()
+	defer inv.Unlock()
+
+	if inv.history == nil {
+		return nil
+	}
+	return append([]Sample(nil), inv.history.samples...)
+}
+
+// record samples the nodes of the last listing, once per sample interval
+func (inv *Inventory) record(metrics *task.Metrics) {
+	h := inv.history
+	if h == nil || len(h.samples) > 0 && inv.updated.Sub(h.samples[len(h.samples)-1].Time) < sampleInterval {
+		return
+	}
+
+	sample := Sample{Time: inv.updated}
+	for _, node := range metrics.Nodes {
+		if node.Unschedulable {
+			continue
+		}
+		capacity := inv.overcommit.apply(&node.Allocatable)
+		addMetric(&sample.Capacity, &capacity)
+		addMetric(&sample.Requested, &node.Requested)
+		addMetric(&sample.Used, &node.Used)
+	}
+	if err := h.append(sample); err != nil {
+		glog.Errorln("record history:", err)
+	}
+}
+
+// append adds the sample, the file is written again once it holds as many
+// samples out of the window as in it
+func (h *history) append(sample Sample) error {
+	h.samples = append(h.samples, sample)
+	i := 0
+	for h.window > 0 && i < len(h.samples) && sample.Time.Sub(h.samples[i].Time) > h.window {
+		i++
+	}
+	h.samples = h.samples[i:]
+	if h.dropped += i; h.dropped >= len(h.samples) {
+		return h.save()
+	}
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "append history")
+	}
+	defer file.Close()
+	return errors.Wrap(json.NewEncoder(file).Encode(&sample), "append history")
+}
+
+// save writes the samples of the window into the file
+func (h *history) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return errors.Wrap(err, "save history")
+	}
+	file, err := os.Create(h.path)
+	if err != nil {
+		return errors.Wrap(err, "save history")
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for i := range h.samples {
+		if err := enc.Encode(&h.samples[i]); err != nil {
+			return errors.Wrap(err, "save history")
+		}
+	}
+	h.dropped = 0
+	return errors.Wrap(w.Flush(), "save history")
+}
+
+func addMetric(m, o *kube.Metric) {
+	m.CPU += o.CPU
+	m.Memory += o.Memory
+	m.Storage += o.Storage
+	m.EphemeralStorage += o.EphemeralStorage
+}
@@ -0,0 +1,356 @@
+// Package inventory tracks the capacity of the nodes available to the tasks,
+// and the resources reserved by the tasks accepted but not running yet
+package inventory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+)
+
+// Source lists the nodes and the tasks, implemented by the task.Tasker
+type Source interface {
+	Metrics() (*task.Metrics, error)
+}
+
+// Reservation is the resources of the pods of a task, placed on the nodes
+// when the task is accepted. It is pending until the pods of the task run,
+// then the requests of the pods take the place of the reservation. The pods
+// of a task updated run meanwhile, their requests are not counted twice.
+type Reservation struct {
+	Name string `json:"name"`
+	// Deployment group of the order of the task, if any
+	Order string             `json:"order,omitempty"`
+	Unit  types.ResourceUnit `json:"unit"`
+	// Node of each pod
+	Nodes  []string `json:"nodes"`
+	Active bool     `json:"active"`
+	// Pending reservations expire after the reservation ttl
+	Created time.Time `json:"created"`
+}
+
+// reservationTTL is how long the pods of a task reserved may take to run,
+// the reservations of the tasks failing to run are then dropped
+const reservationTTL = 15 * time.Minute
+
+// Inventory derives the available capacity of each schedulable node from its
+// overcommitted allocatable resources less the requests of the pods and the
+// pending reservations. The tasks are admitted against it, and it is
+// advertised to the bids and in the status.
+type Inventory struct {
+	sync.Mutex
+	source     Source
+	maxAge     time.Duration
+	overcommit *Overcommit
+	history    *history
+	ttl        time.Duration
+
+	updated time.Time
+	last    *task.Metrics
+	nodes   map[string]*task.NodeMetrics
+	tasks   map[string]*task.TaskMetrics
+
+	reservations map[string]*Reservation
+}
+
+// New creates the inventory of the source, the nodes are listed again when
+// the last listing is older than the maxAge
+func New(source Source, maxAge time.Duration) *Inventory {
+	return &Inventory{
+		source:       source,
+		maxAge:       maxAge,
+		ttl:          reservationTTL,
+		reservations: map[string]*Reservation{},
+	}
+}
+
+// SetReservationTTL sets how long the pending reservations are kept
+func (inv *Inventory) SetReservationTTL(ttl time.Duration) {
+	inv.Lock()
+	defer inv.Unlock()
+	inv.ttl = ttl
+}
+
+// SetOvercommit sets the ratios of the allocatable resources advertised
+func (inv *Inventory) SetOvercommit(overcommit *Overcommit) {
+	inv.Lock()
+	defer inv.Unlock()
+	inv.overcommit = overcommit
+}
+
+// Reserve places the pods of the unit on the available nodes, overcommitted
+// as the bids, the task is refused if they do not fit.
+// The order links the reservation to the bid won for it.
+func (inv *Inventory) Reserve(name, order string, unit types.ResourceUnit, pods uint32) error {
+	inv.Lock()
+	defer inv.Unlock()
+
+	// always against the current requests of the pods
+	if err := inv.refresh(0); err != nil {
+		return err
+	}
+	// a task created again or updated is placed again, in place of its pods,
+	// and keeps its reservation if refused
+	previous := inv.reservations[name]
+	delete(inv.reservations, name)
+
+	available := inv.available()
+	inv.credit(available, name)
+	names := make([]string, 0, len(available))
+	for node := range available {
+		names = append(names, node)
+	}
+	sort.Strings(names)
+
+	reservation := &Reservation{Name: name, Order: order, Unit: unit, Created: time.Now()}
+	for i := uint32(0); i < pods; i++ {
+		node := ""
+		for _, candidate := range names {
+			if available[candidate].Fits(&unit) {
+				node = candidate
+				break
+			}
+		}
+		if node == "" {
+			if previous != nil {
+				inv.reservations[name] = previous
+			}
+			return errors.Errorf("%d pods of %s do not fit in the available capacity, %d placed", pods, name, i)
+		}
+		sub(available[node], &unit)
+		reservation.Nodes = append(reservation.Nodes, node)
+	}
+
+	inv.reservations[name] = reservation
+	return nil
+}
+
+// Release drops the reservation of the task
+func (inv *Inventory) Release(name string) {
+	inv.Lock()
+	defer inv.Unlock()
+	delete(inv.reservations, name)
+}
+
+// Reservations returns the reservations by task
+func (inv *Inventory) Reservations() []Reservation {
+	inv.Lock()
+	defer inv.Unlock()
+
+	res := make([]Reservation, 0, len(inv.reservations))
+	for _, reservation := range inv.reservations {
+		res = append(res, *reservation)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// Available returns the overcommitted available capacity of each schedulable
+// node
+func (inv *Inventory) Available() (map[string]*types.ResourceUnit, error) {
+	inv.Lock()
+	defer inv.Unlock()
+
+	if err := inv.refresh(inv.maxAge); err != nil {
+		return nil, err
+	}
+	return inv.available(), nil
+}
+
+// Metrics returns a copy of the last listing of the source, so the callers
+// within the maxAge share one listing
+func (inv *Inventory) Metrics() (*task.Metrics, error) {
+	inv.Lock()
+	defer inv.Unlock()
+
+	if err := inv.refresh(inv.maxAge); err != nil {
+		return nil, err
+	}
+	metrics := *inv.last
+	return &metrics, nil
+}
+
+// Attributes returns the provider attributes of each schedulable node
+func (inv *Inventory) Attributes() (map[string][]types.ProviderAttribute, error) {
+	inv.Lock()
+	defer inv.Unlock()
+
+	if err := inv.refresh(inv.maxAge); err != nil {
+		return nil, err
+	}
+	attributes := make(map[string][]types.ProviderAttribute, len(inv.nodes))
+	for name, node := range inv.nodes {
+		if !node.Unschedulable {
+			attributes[name] = node.Attributes
+		}
+	}
+	return attributes, nil
+}
+
+// Deployments counts the task deployments of the last listing
+func (inv *Inventory) Deployments() int {
+	inv.Lock()
+	defer inv.Unlock()
+
+	count := 0
+	for _, task := range inv.tasks {
+		if task.Replicas > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Status summarizes the inventory as the proto status, a unit per
+// reservation and per schedulable node
+func (inv *Inventory) Status() (*types.ProviderInventoryStatus, error) {
+	available, err := inv.Available()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &types.ProviderInventoryStatus{
+		Reservations: &types.ProviderInventoryStatus_Reservations{},
+	}
+	for _, reservation := range inv.Reservations() {
+		unit := reservation.Unit
+		unit.CPU *= uint32(len(reservation.Nodes))
+		unit.Memory *= uint64(len(reservation.Nodes))
+		unit.Disk *= uint64(len(reservation.Nodes))
+		if reservation.Active {
+			status.Reservations.Active = append(status.Reservations.Active, &unit)
+		} else {
+			status.Reservations.Pending = append(status.Reservations.Pending, &unit)
+		}
+	}
+
+	nodes := make([]string, 0, len(available))
+	for node := range available {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		status.Available = append(status.Available, available[node])
+	}
+	return status, nil
+}
+
+// refresh lists the nodes and the tasks if the last listing is older than
+// the maxAge, and activates the reservations of the running tasks. The
+// pending reservations older than the ttl are dropped.
+func (inv *Inventory) refresh(maxAge time.Duration) error {
+	for name, reservation := range inv.reservations {
+		if !reservation.Active && time.Since(reservation.Created) > inv.ttl {
+			delete(inv.reservations, name)
+		}
+	}
+	if inv.last != nil && time.Since(inv.updated) < maxAge {
+		return nil
+	}
+
+	metrics, err := inv.source.Metrics()
+	if err != nil {
+		return errors.Wrap(err, "list inventory")
+	}
+	inv.last, inv.updated = metrics, time.Now()
+	inv.nodes, inv.tasks = metrics.Nodes, metrics.Tasks
+	inv.record(metrics)
+
+	for _, reservation := range inv.reservations {
+		if !reservation.Active && inv.runningPods(reservation.Name) >= len(reservation.Nodes) {
+			reservation.Active = true
+		}
+	}
+	return nil
+}
+
+// runningPods counts the pods of the deployments of the task, one per image
+// in the multiple deployments mode
+func (inv *Inventory) runningPods(name string) int {
+	var pods int64
+	for service, metrics := range inv.tasks {
+		if types.IsTaskService(name, service) {
+			pods += metrics.Pods
+		}
+	}
+	return int(pods)
+}
+
+// available is the overcommitted capacity of the schedulable nodes, less the
+// requests of the pods and the pending reservations placed on them, which
+// replace the pods of their tasks
+func (inv *Inventory) available() map[string]*types.ResourceUnit {
+	available := make(map[string]*types.ResourceUnit, len(inv.nodes))
+	for name, node := range inv.nodes {
+		if !node.Unschedulable {
+			available[name] = toUnit(&node.Schedulable)
+			add(available[name], inv.overcommit.extra(&node.Allocatable))
+		}
+	}
+	for _, reservation := range inv.reservations {
+		if reservation.Active {
+			continue
+		}
+		inv.credit(available, reservation.Name)
+		for _, node := range reservation.Nodes {
+			if unit, ok := available[node]; ok {
+				sub(unit, &reservation.Unit)
+			}
+		}
+	}
+	return available
+}
+
+// credit adds back the requests of the running pods of the task
+func (inv *Inventory) credit(available map[string]*types.ResourceUnit, name string) {
+	for service, metrics := range inv.tasks {
+		if !types.IsTaskService(name, service) {
+			continue
+		}
+		for node, requested := range metrics.NodeRequested {
+			if unit, ok := available[node]; ok {
+				add(unit, toUnit(requested))
+			}
+		}
+	}
+}
+
+func toUnit(m *kube.Metric) *types.ResourceUnit {
+	return &types.ResourceUnit{
+		CPU:    uint32(m.CPU),
+		Memory: uint64(m.Memory),
+		Disk:   uint64(m.EphemeralStorage),
+	}
+}
+
+func add(m, unit *types.ResourceUnit) {
+	m.CPU += unit.CPU
+	m.Memory += unit.Memory
+	m.Disk += unit.Disk
+}
+
+// sub subtracts the unit, down to zero
+func sub(m, unit *types.ResourceUnit) {
+	m.CPU -= min32(m.CPU, unit.CPU)
+	m.Memory -= min64(m.Memory, unit.Memory)
+	m.Disk -= min64(m.Disk, unit.Disk)
+}
+
+func min32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,162 @@
+package inventory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type source struct {
+	metrics *task.Metrics
+	lists   int
+}
+
+func (s *source) Metrics() (*task.Metrics, error) {
+	s.lists++
+	return s.metrics, nil
+}
+
+func TestInventory_Reserve(t *testing.T) {
+	src := &source{metrics: &task.Metrics{
+		Nodes: map[string]*task.NodeMetrics{
+			"a": {Schedulable: kube.Metric{CPU: 1000, Memory: 1000, EphemeralStorage: 1000}},
+			"b": {Schedulable: kube.Metric{CPU: 500, Memory: 1000, EphemeralStorage: 1000}},
+			"c": {Schedulable: kube.Metric{CPU: 4000, Memory: 4000}, Unschedulable: true},
+		},
+		Tasks: map[string]*task.TaskMetrics{},
+	}}
+	inv := inventory.New(src, time.Minute)
+	unit := types.ResourceUnit{CPU: 400, Memory: 100, Disk: 100}
+
+	require.NoError(t, inv.Reserve("web", "", unit, 3))
+	assert.Equal(t, []string{"a", "a", "b"}, inv.Reservations()[0].Nodes)
+	assert.Error(t, inv.Reserve("db", "", unit, 1))
+
+	status, err := inv.Status()
+	require.NoError(t, err)
+	assert.Equal(t, []*types.ResourceUnit{{CPU: 1200, Memory: 300, Disk: 300}}, status.Reservations.Pending)
+	assert.Equal(t, []*types.ResourceUnit{{CPU: 200, Memory: 800, Disk: 800}, {CPU: 100, Memory: 900, Disk: 900}},
+		status.Available)
+
+	// the running pods request the resources of the reservation
+	src.metrics.Tasks["web"] = &task.TaskMetrics{Replicas: 3, Pods: 3}
+	require.NoError(t, inv.Reserve("db", "", types.ResourceUnit{CPU: 100}, 1))
+	assert.True(t, inv.Reservations()[1].Active)
+
+	inv.Release("web")
+	assert.Len(t, inv.Reservations(), 1)
+
+	// the reservation refused keeps the previous one
+	assert.Error(t, inv.Reserve("db", "", types.ResourceUnit{CPU: 5000}, 1))
+	require.Len(t, inv.Reservations(), 1)
+	assert.Equal(t, uint32(100), inv.Reservations()[0].Unit.CPU)
+}
+
+func TestInventory_ReservationTTL(t *testing.T) {
+	src := &source{metrics: &task.Metrics{
+		Nodes: map[string]*task.NodeMetrics{"a": {Schedulable: kube.Metric{CPU: 1000}}},
+		Tasks: map[string]*task.TaskMetrics{},
+	}}
+	inv := inventory.New(src, time.Minute)
+	inv.SetReservationTTL(10 * time.Millisecond)
+
+	require.NoError(t, inv.Reserve("web", "", types.ResourceUnit{CPU: 800}, 1))
+	assert.Error(t, inv.Reserve("db", "", types.ResourceUnit{CPU: 800}, 1))
+
+	// the pods of web never ran
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, inv.Reserve("db", "", types.ResourceUnit{CPU: 800}, 1))
+	require.Len(t, inv.Reservations(), 1)
+	assert.Equal(t, "db", inv.Reservations()[0].Name)
+}
+
+func TestInventory_ReserveRunning(t *testing.T) {
+	src := &source{metrics: &task.Metrics{
+		Nodes: map[string]*task.NodeMetrics{
+			"a": {Schedulable: kube.Metric{CPU: 200}},
+			"b": {Schedulable: kube.Metric{CPU: 500}},
+		},
+		Tasks: map[string]*task.TaskMetrics{
+			"web": {Replicas: 2, Pods: 2, NodeRequested: map[string]*kube.Metric{"a": {CPU: 800}}},
+		},
+	}}
+	inv := inventory.New(src, time.Minute)
+
+	// the task scaled up is placed in place of its pods
+	require.NoError(t, inv.Reserve("web", "", types.ResourceUnit{CPU: 400}, 3))
+	assert.Equal(t, []string{"a", "a", "b"}, inv.Reservations()[0].Nodes)
+
+	available, err := inv.Available()
+	require.NoError(t, err)
+	assert.Equal(t, &types.ResourceUnit{CPU: 200}, available["a"])
+	assert.Equal(t, &types.ResourceUnit{CPU: 100}, available["b"])
+}
+
+func TestInventory_Metrics(t *testing.T) {
+	src := &source{metrics: &task.Metrics{TotalCPU: 1000}}
+	inv := inventory.New(src, time.Minute)
+
+	// the heartbeat and the status share one listing
+	metrics, err := inv.Metrics()
+	require.NoError(t, err)
+	metrics.TotalCPU = 1
+	_, err = inv.Status()
+	require.NoError(t, err)
+	metrics, err = inv.Metrics()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), metrics.TotalCPU)
+	assert.Equal(t, 1, src.lists)
+}
+
+func TestInventory_Overcommit(t *testing.T) {
+	src := &source{metrics: &task.Metrics{
+		Nodes: map[string]*task.NodeMetrics{
+			"a": {
+				Allocatable: kube.Metric{CPU: 1000, Memory: 1000},
+				Requested:   kube.Metric{CPU: 800, Memory: 800},
+				Schedulable: kube.Metric{CPU: 200, Memory: 200},
+			},
+		},
+	}}
+	inv := inventory.New(src, time.Minute)
+	overcommit, err := inventory.ParseOvercommit("cpu=2,memory=1.5")
+	require.NoError(t, err)
+	inv.SetOvercommit(overcommit)
+
+	available, err := inv.Available()
+	require.NoError(t, err)
+	assert.Equal(t, &types.ResourceUnit{CPU: 1200, Memory: 700}, available["a"])
+	// the tasks are admitted against the capacity bid for
+	assert.Error(t, inv.Reserve("web", "", types.ResourceUnit{CPU: 1300}, 1))
+	require.NoError(t, inv.Reserve("web", "", types.ResourceUnit{CPU: 1200}, 1))
+
+	_, err = inventory.ParseOvercommit("gpu=2")
+	assert.Error(t, err)
+	_, err = inventory.ParseOvercommit("cpu=0.5")
+	assert.Error(t, err)
+}
+
+func TestForecasts(t *testing.T) {
+	start := time.Unix(1550000000, 0)
+	var samples []inventory.Sample
+	for i := 0; i < 5; i++ {
+		samples = append(samples, inventory.Sample{
+			Time:      start.Add(time.Duration(i) * time.Hour),
+			Capacity:  kube.Metric{CPU: 1000, Memory: 1000},
+			Requested: kube.Metric{CPU: int64(100 * i), Memory: 500},
+		})
+	}
+
+	forecasts := inventory.Forecasts(samples)
+	require.Len(t, forecasts, 3)
+	assert.Equal(t, int64(600), forecasts[0].Headroom)
+	assert.InDelta(t, 100, forecasts[0].Trend, 0.001)
+	assert.WithinDuration(t, start.Add(10*time.Hour), forecasts[0].Exhausted, time.Second)
+	assert.True(t, forecasts[1].Exhausted.IsZero())
+}
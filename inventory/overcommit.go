@@ -0,0 +1,80 @@
+package inventory
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+)
+
+// Overcommit is the ratio, at least 1, of the allocatable resources of the
+// nodes, 1 if not set, as the burstable pods request less than their units.
+// The bids, the status and the admission of the tasks all use the
+// overcommitted capacity.
+type Overcommit struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	Disk   float64 `json:"disk"`
+}
+
+// ParseOvercommit parses the ratios of the resources, e.g. cpu=2,memory=1.5
+func ParseOvercommit(s string) (*Overcommit, error) {
+	o := &Overcommit{}
+	if s == "" {
+		return o, nil
+	}
+
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("overcommit %s: not resource=ratio", item)
+		}
+		ratio, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil || ratio < 1 {
+			return nil, errors.Errorf("overcommit %s: not a ratio of at least 1", item)
+		}
+		switch strings.TrimSpace(kv[0]) {
+		case "cpu":
+			o.CPU = ratio
+		case "memory":
+			o.Memory = ratio
+		case "disk":
+			o.Disk = ratio
+		default:
+			return nil, errors.Errorf("overcommit %s: unknown resource", item)
+		}
+	}
+	return o, nil
+}
+
+// apply scales the allocatable resources by the ratios
+func (o *Overcommit) apply(m *kube.Metric) kube.Metric {
+	if o == nil {
+		return *m
+	}
+	return kube.Metric{
+		CPU:              scale(m.CPU, o.CPU),
+		Memory:           scale(m.Memory, o.Memory),
+		Storage:          m.Storage,
+		EphemeralStorage: scale(m.EphemeralStorage, o.Disk),
+	}
+}
+
+// extra is the overcommitted resources over the allocatable ones
+func (o *Overcommit) extra(m *kube.Metric) *types.ResourceUnit {
+	capacity := o.apply(m)
+	return &types.ResourceUnit{
+		CPU:    uint32(capacity.CPU - m.CPU),
+		Memory: uint64(capacity.Memory - m.Memory),
+		Disk:   uint64(capacity.EphemeralStorage - m.EphemeralStorage),
+	}
+}
+
+func scale(v int64, ratio float64) int64 {
+	if ratio == 0 {
+		return v
+	}
+	return int64(float64(v) * ratio)
+}
@@ -4,18 +4,32 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
+	"github.com/Ankr-network/dccn-daemon/bidengine"
+	"github.com/Ankr-network/dccn-daemon/chain"
 	"github.com/Ankr-network/dccn-daemon/daemon"
+	"github.com/Ankr-network/dccn-daemon/inventory"
+	"github.com/Ankr-network/dccn-daemon/metering"
 	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	dccntypes "github.com/Ankr-network/dccn-daemon/types"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tendermint/tendermint/abci/server"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/rpc/client"
-	"github.com/tendermint/tendermint/types"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/homedir"
 )
@@ -25,7 +39,10 @@ var (
 	commit  string
 	date    string
 
-	kubeCfg = filepath.Join(homedir.HomeDir(), ".kube", "config")
+	kubeCfg     = filepath.Join(homedir.HomeDir(), ".kube", "config")
+	providerKey = filepath.Join(homedir.HomeDir(), ".dccn", "provider_key.json")
+	outboxFile  = filepath.Join(homedir.HomeDir(), ".dccn", "metering_outbox.json")
+	historyFile = filepath.Join(homedir.HomeDir(), ".dccn", "usage_history.jsonl")
 )
 
 func main() {
@@ -49,6 +66,8 @@ func main() {
 	rootCmd.AddCommand(startCmd())
 	rootCmd.AddCommand(blockchainCmd())
 	rootCmd.AddCommand(metricCmd())
+	rootCmd.AddCommand(meteringCmd())
+	rootCmd.AddCommand(abciCmd())
 	rootCmd.Execute()
 }
 
@@ -76,6 +95,7 @@ func metricCmd() *cobra.Command {
 	ns := cmd.Flags().StringP("namespace", "n", apiv1.NamespaceDefault, "kubernetes namespace")
 	host := cmd.Flags().String("ingress-host", "localhost", "kubernetes ingress host")
 	cfgpath := cmd.Flags().String("k8s-cfg", kubeCfg, "kubernetes config")
+	by := cmd.Flags().String("by", "", "table of the metrics by node or task, json of all if empty")
 
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		client, err := task.NewTasker(*cfgpath, *ns, *host)
@@ -84,10 +104,98 @@ func metricCmd() *cobra.Command {
 		metrics, err := client.Metrics()
 		exitOnErr(err)
 
-		data, err := json.MarshalIndent(metrics, "", "    ")
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		switch *by {
+		case "":
+			data, err := json.MarshalIndent(metrics, "", "    ")
+			exitOnErr(err)
+			fmt.Printf("%s\n", data)
+			return
+
+		case "node":
+			names := make([]string, 0, len(metrics.Nodes))
+			for name := range metrics.Nodes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Fprintln(w, "NODE\tSCHEDULABLE\tCPU ALLOCATABLE\tCPU USED\tCPU REQUESTED\t"+
+				"MEMORY ALLOCATABLE\tMEMORY USED\tMEMORY REQUESTED\tPODS\tIMAGES\tRX BYTES\tTX BYTES")
+			for _, name := range names {
+				node := metrics.Nodes[name]
+				fmt.Fprintf(w, "%s\t%t\t%d\t%d\t%d\t%d\t%d\t%d\t%d/%d\t%d\t%d\t%d\n", name, !node.Unschedulable,
+					node.Allocatable.CPU, node.Used.CPU, node.Requested.CPU,
+					node.Allocatable.Memory, node.Used.Memory, node.Requested.Memory,
+					node.Pods, node.PodCapacity, len(node.Images), node.Network.RxBytes, node.Network.TxBytes)
+			}
+
+		case "task":
+			names := make([]string, 0, len(metrics.Tasks))
+			for name := range metrics.Tasks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Fprintln(w, "TASK\tREPLICAS\tPODS\tCPU REQUESTED\tCPU CONSUMED\t"+
+				"MEMORY REQUESTED\tMEMORY CONSUMED\tRX BYTES\tTX BYTES")
+			for _, name := range names {
+				item := metrics.Tasks[name]
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", name, item.Replicas, item.Pods,
+					item.Requested.CPU, item.Consumed.CPU, item.Requested.Memory, item.Consumed.Memory,
+					item.Network.RxBytes, item.Network.TxBytes)
+			}
+
+		default:
+			exitOnErr(errors.Errorf("unknown view: %s", *by))
+		}
+		exitOnErr(w.Flush())
+	}
+
+	cmd.AddCommand(forecastCmd())
+	return cmd
+}
+
+func forecastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "forecast the capacity of the datacenter",
+		Long:  "forecast projects when the requests of the pods exhaust the capacity, from the usage history of the daemon",
+	}
+
+	history := cmd.Flags().String("history", historyFile, "usage history of the daemon")
+	window := cmd.Flags().Duration("window", 7*24*time.Hour, "samples of the history fitted, all if 0")
+	output := cmd.Flags().StringP("output", "o", "table", "output format: table, json")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		samples, err := inventory.ReadHistory(*history, *window)
 		exitOnErr(err)
+		if len(samples) == 0 {
+			exitOnErr(errors.New("no samples in the history"))
+		}
+		forecasts := inventory.Forecasts(samples)
 
-		fmt.Printf("%s\n", data)
+		switch *output {
+		case "json":
+			data, err := json.MarshalIndent(forecasts, "", "    ")
+			exitOnErr(err)
+			fmt.Printf("%s\n", data)
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintf(w, "%d samples from %s to %s\n", len(samples),
+				samples[0].Time.Format(time.RFC3339), samples[len(samples)-1].Time.Format(time.RFC3339))
+			fmt.Fprintln(w, "RESOURCE\tCAPACITY\tREQUESTED\tUSED\tHEADROOM\tTREND PER HOUR\tEXHAUSTED")
+			for _, forecast := range forecasts {
+				exhausted := "never"
+				if !forecast.Exhausted.IsZero() {
+					exhausted = forecast.Exhausted.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f\t%s\n", forecast.Resource, forecast.Capacity,
+					forecast.Requested, forecast.Used, forecast.Headroom, forecast.Trend, exhausted)
+			}
+			exitOnErr(w.Flush())
+		default:
+			exitOnErr(errors.Errorf("unknown output: %s", *output))
+		}
 	}
 
 	return cmd
@@ -108,6 +216,20 @@ func startCmd() *cobra.Command {
 	tendermintServer := cmd.PersistentFlags().StringP("tendermint-server", "S", "127.0.0.1", "special tendermint grpc server")
 	tendermintPort := cmd.PersistentFlags().Uint32P("tendermint-port", "P", 26657, "special tendermint grpc port")
 	tendermintWsEndpoint := cmd.PersistentFlags().StringP("tendermint-websocket-endpoint", "W", "/websocket", "special tendermint websocket endpoint")
+	taskerFlags := addTaskerFlags(cmd.Flags())
+	ledger := cmd.Flags().String("metering-ledger", "", "append-only file of the metering records, disabled if empty")
+	checkpoint := cmd.Flags().String("metering-checkpoint", "", "metering checkpoint file, or configmap:<name> in cluster, disabled if empty")
+	keyPath := cmd.Flags().String("provider-key", providerKey, "ed25519 key signing the metering reports, mounted from a secret in cluster")
+	outboxPath := cmd.Flags().String("metering-outbox", outboxFile, "queue of the metering reports not committed to tendermint, in memory if empty")
+	metricsAddr := cmd.Flags().String("metrics-addr", ":9102", "address of the prometheus /metrics endpoint, disabled if empty")
+	statusAddr := cmd.Flags().String("status-addr", ":50052", "address of the grpc status server, disabled if empty")
+	reserve := cmd.Flags().Bool("reserve", true, "reserve the resources of the tasks created or updated, and refuse those not fitting")
+	overcommit := cmd.Flags().String("overcommit", "", "ratios of the allocatable resources bid for, reported and reserved, e.g. cpu=2,memory=1.2, 1 if not set")
+	historyPath := cmd.Flags().String("usage-history", historyFile, "rolling history of the node usage for the forecasts, disabled if empty")
+	historyWindow := cmd.Flags().Duration("usage-window", 7*24*time.Hour, "samples kept in the usage history")
+	bidOrders := cmd.Flags().String("bid-orders", "", "source of the deployment orders bid for: chain, or a json file of orders for testing, disabled if empty")
+	bidPricing := cmd.Flags().String("bid-pricing", "", "json pricing policy of the bids, per unit hour, the default policy if empty")
+	bidInterval := cmd.Flags().Duration("bid-interval", 10*time.Second, "interval of polling the orders")
 
 	cmd.PreRun = func(*cobra.Command, []string) {
 		glog.Infoln("version:", version, "commit:", commit, "date:", date)
@@ -128,10 +250,60 @@ func startCmd() *cobra.Command {
 	}
 
 	cmd.Run = func(cmd *cobra.Command, args []string) {
-		glog.Infof("Starting, hub: %s:%d", *server, *port)
-		glog.Fatalln(daemon.ServeTask(*cfgpath, *ns, *host,
-			fmt.Sprintf("%s:%d", *server, *port), args[0],
-			fmt.Sprintf("%s:%d", *tendermintServer, *tendermintPort), *tendermintWsEndpoint))
+		tasker, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
+		exitOnErr(err)
+
+		engine, err := newMeteringEngine(tasker, *ledger, *checkpoint)
+		exitOnErr(err)
+		// a generated key would sign for an address not registered, and be
+		// lost with the container
+		key, err := daemon.LoadKey(*keyPath)
+		exitOnErr(errors.Wrap(err, "provider key, generated by bc key --generate"))
+		tendermint := client.NewHTTP(fmt.Sprintf("tcp://%s:%d", *tendermintServer, *tendermintPort), *tendermintWsEndpoint)
+		outbox, err := daemon.NewOutbox(*outboxPath, dccntypes.TxEnvelope_METERING, args[0], *ns, key, tendermint)
+		exitOnErr(err)
+
+		inv := inventory.New(tasker, 10*time.Second)
+		ratios, err := inventory.ParseOvercommit(*overcommit)
+		exitOnErr(err)
+		inv.SetOvercommit(ratios)
+		if *historyPath != "" {
+			exitOnErr(inv.SetHistory(*historyPath, *historyWindow))
+		}
+		if *reserve {
+			tasker.SetReserver(inv)
+		}
+
+		// the orders of the hub tasks are looked up on the chain unless bid
+		// for from a file
+		source := bidengine.NewChainSource(tendermint, key, args[0])
+		if *bidOrders != "" && *bidOrders != "chain" {
+			source = bidengine.NewFileSource(*bidOrders, dccntypes.Address(key.PubKey()))
+		}
+		var bids *bidengine.Engine
+		if *bidOrders != "" {
+			pricing, err := bidengine.LoadPricing(*bidPricing)
+			exitOnErr(err)
+			bids = bidengine.New(source, inv, pricing, dccntypes.Address(key.PubKey()))
+			go bids.Run(*bidInterval, nil)
+		}
+		if *statusAddr != "" {
+			server := daemon.NewStatusServer(tasker, inv, bids, dccntypes.Address(key.PubKey()),
+				&dccntypes.Version{Version: version, Commit: commit, Date: date})
+			go func() {
+				glog.Errorln("status server stopped:", daemon.ServeStatus(*statusAddr, server))
+			}()
+		}
+		if *metricsAddr != "" {
+			go func() {
+				glog.Errorln("metrics server stopped:", daemon.ServeMetrics(*metricsAddr, tasker, inv, engine, outbox))
+			}()
+		}
+
+		glog.Infof("Starting, hub: %s:%d, provider address: %s", *server, *port, dccntypes.Address(key.PubKey()))
+		orders := daemon.NewOrders(source, dccntypes.Address(key.PubKey()))
+		glog.Fatalln(daemon.ServeTask(tasker, engine, outbox, inv, orders,
+			fmt.Sprintf("%s:%d", *server, *port), args[0]))
 	}
 
 	return cmd
@@ -147,6 +319,8 @@ func taskCmd() *cobra.Command {
 	ns := cmd.PersistentFlags().StringP("namespace", "n", apiv1.NamespaceDefault, "kubernetes namespace")
 	host := cmd.PersistentFlags().String("ingress-host", "localhost", "kubernetes ingress host")
 	cfgpath := cmd.PersistentFlags().String("k8s-cfg", kubeCfg, "kubernetes config")
+	options := cmd.PersistentFlags().String("options", "", "service options json file, e.g. domains, tls and probes")
+	taskerFlags := addTaskerFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "create <name> <images>",
@@ -154,10 +328,14 @@ func taskCmd() *cobra.Command {
 		Long:  "create a new deploy task with your images",
 		Args:  cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
-			client, err := task.NewTasker(*cfgpath, *ns, *host)
+			opts, err := loadOptions(*options)
+			exitOnErr(err)
+
+			client, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
 			exitOnErr(err)
 
-			exitOnErr(client.CreateTasks(args[0], args[1:]...))
+			exitOnErr(client.CreateTasks(args[0], opts, args[1:]...))
+			exitOnErr(client.WaitReady(args[0]))
 		},
 	})
 
@@ -170,10 +348,59 @@ func taskCmd() *cobra.Command {
 			replicas, err := strconv.ParseUint(args[2], 10, 32)
 			exitOnErr(err)
 
-			client, err := task.NewTasker(*cfgpath, *ns, *host)
+			opts, err := loadOptions(*options)
+			exitOnErr(err)
+
+			client, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
+			exitOnErr(err)
+
+			exitOnErr(client.UpdateTask(args[0], strings.Split(args[1], ","), uint32(replicas), 80, 80, opts))
+			exitOnErr(client.WaitReady(args[0]))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "canary <name> <image> <weight>",
+		Short: "canary deploy task",
+		Long:  "run the image beside the task with the weight percent of its ingress traffic",
+		Args:  cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			weight, err := strconv.Atoi(args[2])
+			exitOnErr(err)
+
+			opts, err := loadOptions(*options)
+			exitOnErr(err)
+
+			client, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
+			exitOnErr(err)
+
+			exitOnErr(client.Canary(args[0], args[1], weight, opts))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "promote <name>",
+		Short: "promote task canary",
+		Long:  "roll the canary pod template out to the task and remove the canary",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
 			exitOnErr(err)
 
-			exitOnErr(client.UpdateTask(args[0], args[1], uint32(replicas), 80, 80))
+			exitOnErr(client.Promote(args[0]))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "abort <name>",
+		Short: "abort task canary",
+		Long:  "remove the canary, all the traffic goes back to the task",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := taskerFlags.newTasker(*cfgpath, *ns, *host)
+			exitOnErr(err)
+
+			exitOnErr(client.AbortCanary(args[0]))
 		},
 	})
 
@@ -234,7 +461,7 @@ func jobCmd() *cobra.Command {
 			if len(args) >= 3 {
 				crontab = args[2]
 			}
-			exitOnErr(client.CreateJobs(args[0], crontab, args[1:]...))
+			exitOnErr(client.CreateJobs(args[0], crontab, nil, args[1:]...))
 		},
 	})
 
@@ -258,54 +485,290 @@ func jobCmd() *cobra.Command {
 	return cmd
 }
 
-func blockchainCmd() *cobra.Command {
+func abciCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "bc",
-		Short: "blockchain service",
-		Long:  "send or query message on blockchain",
+		Use:   "abci",
+		Short: "run the tendermint application",
+		Long:  "abci serves the metering and deployment application to a tendermint node",
 	}
 
-	ns := cmd.PersistentFlags().StringP("namespace", "n", apiv1.NamespaceDefault, "kubernetes namespace")
-	host := cmd.PersistentFlags().String("ingress-host", "localhost", "kubernetes ingress host")
-	cfgpath := cmd.PersistentFlags().String("k8s-cfg", kubeCfg, "kubernetes config")
+	addr := cmd.Flags().String("addr", "tcp://0.0.0.0:26658", "address tendermint connects to")
+	transport := cmd.Flags().String("transport", "socket", "abci transport: socket, grpc")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		srv, err := server.NewServer(*addr, *transport, chain.NewApp())
+		exitOnErr(err)
+		exitOnErr(srv.Start())
+		glog.Infoln("abci listening on", *addr)
 
-	server := cmd.PersistentFlags().StringP("tendermint-server", "s", "127.0.0.1", "special tendermint grpc server")
-	port := cmd.PersistentFlags().Uint32P("tendermint-port", "p", 26657, "special tendermint grpc port")
-	wsEndpoint := cmd.PersistentFlags().StringP("tendermint-websocket-endpoint", "w", "/websocket", "special tendermint websocket endpoint")
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		exitOnErr(srv.Stop())
+	}
 
-	cmd.AddCommand(&cobra.Command{
-		Use:   "metering <dc-name>",
-		Short: "store metering info into tendermint",
-		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			// collect data
-			clientTask, err := task.NewTasker(*cfgpath, *ns, *host)
-			exitOnErr(err)
+	return cmd
+}
 
-			metering, err := clientTask.Metering()
-			exitOnErr(err)
+func meteringCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metering",
+		Short: "metering records",
+		Long:  "metering reads the ledger of the metering records",
+	}
+
+	ledger := cmd.PersistentFlags().String("ledger", "", "metering ledger of the daemon")
 
-			data, err := json.Marshal(metering)
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	invoice := &cobra.Command{
+		Use:   "invoice",
+		Short: "invoice a deployment",
+		Long: "invoice prices the metered usage of a deployment by the resource groups of its order won on the chain, " +
+			"or of the order written by hand in the options file",
+	}
+	from := invoice.Flags().String("from", month.Format(time.RFC3339), "start of the billing period, RFC3339")
+	to := invoice.Flags().String("to", now.Format(time.RFC3339), "end of the billing period, RFC3339")
+	deployment := invoice.Flags().String("deployment", "", "name of the deployment")
+	order := invoice.Flags().String("order", "", "deployment group won on the chain, <deployment>/<seq>, whose resource groups are invoiced")
+	options := invoice.Flags().String("options", "", "json file of the deployment options with the order written by hand, instead of --order")
+	tendermintServer := invoice.Flags().StringP("tendermint-server", "S", "127.0.0.1", "special tendermint grpc server")
+	tendermintPort := invoice.Flags().Uint32P("tendermint-port", "P", 26657, "special tendermint grpc port")
+	format := invoice.Flags().String("format", "json", "statement format: json, csv")
+	invoice.Run = func(cmd *cobra.Command, args []string) {
+		if *ledger == "" || *deployment == "" {
+			exitOnErr(errors.New("ledger and deployment must set"))
+		}
+		start, err := time.Parse(time.RFC3339, *from)
+		exitOnErr(err)
+		end, err := time.Parse(time.RFC3339, *to)
+		exitOnErr(err)
+		var groups []dccntypes.ResourceGroup
+		switch {
+		case *order != "":
+			tendermint := client.NewHTTP(fmt.Sprintf("tcp://%s:%d", *tendermintServer, *tendermintPort), "/websocket")
+			groups, err = loadOrder(tendermint, *order)
 			exitOnErr(err)
+		case *options != "":
+			opts, err := loadOptions(*options)
+			exitOnErr(err)
+			groups = opts.GetOrder()
+		default:
+			exitOnErr(errors.New("order or options must set"))
+		}
 
-			// write into tendermint
-			c := client.NewHTTP(fmt.Sprintf("tcp://%s:%d", *server, *port), *wsEndpoint)
+		inv, err := metering.NewLedgerInvoice(*ledger, *deployment, start, end, groups)
+		exitOnErr(err)
 
-			_, err = c.BroadcastTxCommit(types.Tx(
-				fmt.Sprintf("%s_%s=%s", args[0], *ns, data)))
+		switch *format {
+		case "json":
+			data, err := json.MarshalIndent(inv, "", "    ")
 			exitOnErr(err)
+			fmt.Printf("%s\n", data)
+		case "csv":
+			exitOnErr(inv.WriteCSV(os.Stdout))
+		default:
+			exitOnErr(errors.Errorf("unknown format: %s", *format))
+		}
+	}
+	cmd.AddCommand(invoice)
 
-			// query
-			resp, err := c.ABCIQuery(*wsEndpoint, cmn.HexBytes(fmt.Sprintf("%s_%s", args[0], *ns)))
-			exitOnErr(err)
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "export the metering records",
+		Long: "export writes the usage of the pods per sample interval to the stdout, " +
+			"or every period into rotated files of the directory",
+	}
+	exportFrom := export.Flags().String("from", "", "start of the export period, RFC3339, unbounded if empty")
+	exportTo := export.Flags().String("to", "", "end of the export period, RFC3339, unbounded if empty")
+	exportFormat := export.Flags().String("format", metering.FormatCSV, "export format: csv, jsonl")
+	dir := export.Flags().String("dir", "", "directory of the scheduled exports, a file per period")
+	every := export.Flags().Duration("every", time.Hour, "period of the scheduled exports")
+	delay := export.Flags().Duration("delay", time.Minute, "wait after the end of a period before its export")
+	keep := export.Flags().Int("keep", 0, "number of the export files kept, all if 0")
+	export.Run = func(cmd *cobra.Command, args []string) {
+		if *ledger == "" {
+			exitOnErr(errors.New("ledger must set"))
+		}
+		if *exportFormat != metering.FormatCSV && *exportFormat != metering.FormatJSONL {
+			exitOnErr(errors.Errorf("unknown format: %s", *exportFormat))
+		}
 
-			fmt.Printf("%s\n", resp.Response.GetValue())
-		},
-	})
+		if *dir != "" {
+			if *every <= 0 {
+				exitOnErr(errors.New("every must be positive"))
+			}
+			exporter := &metering.Exporter{
+				Ledger: *ledger,
+				Dir:    *dir,
+				Format: *exportFormat,
+				Period: *every,
+				Delay:  *delay,
+				Keep:   *keep,
+			}
+			stop := make(chan struct{})
+			go func() {
+				sig := make(chan os.Signal, 1)
+				signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+				<-sig
+				close(stop)
+			}()
+			glog.Infoln("metering exports into", *dir, "every", *every)
+			exporter.Run(stop)
+			return
+		}
+
+		var start, end time.Time
+		var err error
+		if *exportFrom != "" {
+			start, err = time.Parse(time.RFC3339, *exportFrom)
+			exitOnErr(err)
+		}
+		if *exportTo != "" {
+			end, err = time.Parse(time.RFC3339, *exportTo)
+			exitOnErr(err)
+		}
+		_, err = metering.Export(*ledger, start, end, *exportFormat, os.Stdout)
+		exitOnErr(err)
+	}
+	cmd.AddCommand(export)
 
 	return cmd
 }
 
+// taskerFlags are the tasker settings shared by the commands creating tasks
+type taskerFlags struct {
+	issuer       *string
+	ingressClass *string
+	exposeType   *string
+	nodePorts    *string
+	readyTimeout *time.Duration
+	multiImage   *string
+	policy       *string
+	nonRoot      *bool
+	imagePolicy  *string
+}
+
+func addTaskerFlags(flags *pflag.FlagSet) *taskerFlags {
+	return &taskerFlags{
+		issuer:       flags.String("tls-issuer", "", "issuer of task certificates, support: self-signed"),
+		ingressClass: flags.String("ingress-class", "", "ingress class of task ingresses, default by cluster"),
+		exposeType:   flags.String("expose-service-type", string(apiv1.ServiceTypeNodePort), "service type of global TCP/UDP exposes: NodePort, LoadBalancer"),
+		nodePorts:    flags.String("node-port-range", "30000-32767", "node ports allocated to global TCP/UDP exposes"),
+		readyTimeout: flags.Duration("ready-timeout", time.Minute, "wait deployments ready, reported apart from the task operations, 0 to not wait"),
+		multiImage:   flags.String("multi-image-mode", task.MultiImageDeployments, "how the images of a task run: deployments, pod"),
+		policy:       flags.String("security-policy", "", "json file of the container security relaxations tenants may request"),
+		nonRoot:      flags.Bool("run-as-non-root", false, "run the containers of the tasks without security options as non-root without capabilities"),
+		imagePolicy:  flags.String("image-policy", "", "json file of the images tenants may run"),
+	}
+}
+
+func (f *taskerFlags) newTasker(cfgpath, ns, host string) (*task.Tasker, error) {
+	tasker, err := task.NewTasker(cfgpath, ns, host)
+	if err != nil {
+		return nil, err
+	}
+	tasker.SetIngressClass(*f.ingressClass)
+	tasker.SetReadyTimeout(*f.readyTimeout)
+	if err := tasker.SetMultiImageMode(*f.multiImage); err != nil {
+		return nil, err
+	}
+	tasker.SetRunAsNonRoot(*f.nonRoot)
+	if *f.policy != "" {
+		policy := &task.SecurityPolicy{}
+		if err := loadJSON(*f.policy, policy); err != nil {
+			return nil, errors.Wrap(err, "security policy")
+		}
+		tasker.SetSecurityPolicy(policy)
+	}
+	if *f.imagePolicy != "" {
+		policy := &task.ImagePolicy{}
+		if err := loadJSON(*f.imagePolicy, policy); err != nil {
+			return nil, errors.Wrap(err, "image policy")
+		}
+		tasker.SetImagePolicy(policy)
+	}
+
+	switch *f.issuer {
+	case "":
+	case "self-signed":
+		tasker.SetCertIssuer(kube.NewSelfSignedIssuer(90 * 24 * time.Hour))
+	default:
+		return nil, errors.Errorf("unknown tls issuer: %s", *f.issuer)
+	}
+
+	var min, max int32
+	if _, err := fmt.Sscanf(*f.nodePorts, "%d-%d", &min, &max); err != nil {
+		return nil, errors.Wrapf(err, "node port range %s", *f.nodePorts)
+	}
+	if err := tasker.SetExposeService(apiv1.ServiceType(*f.exposeType), min, max); err != nil {
+		return nil, err
+	}
+	return tasker, nil
+}
+
+// newMeteringEngine restores the metering engine from the checkpoint
+func newMeteringEngine(tasker *task.Tasker, ledger, checkpoint string) (*metering.Engine, error) {
+	engine := metering.NewEngine(tasker)
+	if ledger != "" {
+		l, err := metering.OpenLedger(ledger)
+		if err != nil {
+			return nil, err
+		}
+		engine.SetLedger(l)
+	}
+
+	switch {
+	case checkpoint == "":
+		return engine, nil
+	case strings.HasPrefix(checkpoint, "configmap:"):
+		engine.SetCheckpointStore(metering.NewConfigMapStore(tasker, strings.TrimPrefix(checkpoint, "configmap:")))
+	default:
+		engine.SetCheckpointStore(metering.NewFileStore(checkpoint))
+	}
+	return engine, engine.Restore()
+}
+
+func loadOptions(path string) (*dccntypes.ServiceOptions, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	options := &dccntypes.ServiceOptions{}
+	if err := loadJSON(path, options); err != nil {
+		return nil, errors.Wrap(err, "options")
+	}
+	return options, nil
+}
+
+// loadOrder reads the resource groups of the order on the chain, the group
+// must be ordered
+func loadOrder(tendermint *client.HTTP, id string) ([]dccntypes.ResourceGroup, error) {
+	res, err := tendermint.ABCIQuery(chain.QueryOrder, cmn.HexBytes(id))
+	if err != nil {
+		return nil, errors.Wrap(err, "query order")
+	}
+	if res.Response.Code != chain.CodeOK {
+		return nil, errors.Errorf("query order %s: %s", id, res.Response.Log)
+	}
+	order := &chain.Order{}
+	if err := json.Unmarshal(res.Response.Value, order); err != nil {
+		return nil, errors.Wrap(err, "query order")
+	}
+	if order.Group.State != dccntypes.DeploymentGroup_ORDERED {
+		return nil, errors.Errorf("order %s is %s", id, order.Group.State)
+	}
+	return order.Group.Resources, nil
+}
+
+func loadJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read")
+	}
+	return errors.Wrap(json.Unmarshal(data, v), "parse")
+}
+
 func exitOnErr(err error, a ...interface{}) {
 	if err == nil {
 		return
@@ -0,0 +1,113 @@
+package metering
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Checkpoint is the state of the engine after a sample
+type Checkpoint struct {
+	Time  time.Time         `json:"time"`
+	Pods  map[string]Record `json:"pods"`
+	Tasks map[string]Record `json:"tasks"`
+}
+
+// CheckpointStore persists the checkpoint of the engine, Load returns nil
+// if none saved
+type CheckpointStore interface {
+	Load() (*Checkpoint, error)
+	Save(cp *Checkpoint) error
+}
+
+type fileStore struct {
+	path string
+}
+
+// NewFileStore saves the checkpoint into the file, replaced atomically
+func NewFileStore(path string) CheckpointStore {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Load() (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "load checkpoint")
+	}
+
+	cp := &Checkpoint{}
+	return cp, errors.Wrap(json.Unmarshal(data, cp), "load checkpoint")
+}
+
+func (s *fileStore) Save(cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "save checkpoint")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".")
+	if err != nil {
+		return errors.Wrap(err, "save checkpoint")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "save checkpoint")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "save checkpoint")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "save checkpoint")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "save checkpoint")
+}
+
+// ConfigMaps reads and writes config maps, implemented by task.Tasker
+type ConfigMaps interface {
+	ConfigMap(name string) (map[string]string, error)
+	SaveConfigMap(name string, data map[string]string) error
+}
+
+const checkpointKey = "checkpoint.json"
+
+type configMapStore struct {
+	configMaps ConfigMaps
+	name       string
+}
+
+// NewConfigMapStore saves the checkpoint into the config map, for the daemon
+// running in cluster without a persistent volume
+func NewConfigMapStore(configMaps ConfigMaps, name string) CheckpointStore {
+	return &configMapStore{configMaps: configMaps, name: name}
+}
+
+func (s *configMapStore) Load() (*Checkpoint, error) {
+	data, err := s.configMaps.ConfigMap(s.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "load checkpoint")
+	}
+	if data[checkpointKey] == "" {
+		return nil, nil
+	}
+
+	cp := &Checkpoint{}
+	return cp, errors.Wrap(json.Unmarshal([]byte(data[checkpointKey]), cp), "load checkpoint")
+}
+
+func (s *configMapStore) Save(cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "save checkpoint")
+	}
+	return errors.Wrap(s.configMaps.SaveConfigMap(s.name, map[string]string{checkpointKey: string(data)}),
+		"save checkpoint")
+}
@@ -0,0 +1,223 @@
+// Package metering integrates the resources used by the tasks over time
+package metering
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+)
+
+// Usage is the resources integrated over time, CPU in milli core-seconds,
+// memory and disk in byte-seconds
+type Usage struct {
+	CPU    uint64 `json:"cpu"`
+	Memory uint64 `json:"memory"`
+	Disk   uint64 `json:"disk"`
+}
+
+// Add adds the usage
+func (u *Usage) Add(o Usage) {
+	u.CPU += o.CPU
+	u.Memory += o.Memory
+	u.Disk += o.Disk
+}
+
+// integrate returns the usage of the metric held for the duration
+func integrate(m kube.Metric, d time.Duration) Usage {
+	seconds := d.Seconds()
+	return Usage{
+		CPU:    uint64(float64(m.CPU) * seconds),
+		Memory: uint64(float64(m.Memory) * seconds),
+		Disk:   uint64(float64(m.EphemeralStorage) * seconds),
+	}
+}
+
+// Record is the metering of a pod or a task from Start to End
+type Record struct {
+	Requested Usage `json:"requested"`
+	// Limits of the pods, zero in the records before the pods had requests
+	// apart from their limits
+	Limited  Usage     `json:"limited"`
+	Consumed Usage     `json:"consumed"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+func (r *Record) add(requested, limited, consumed Usage, start, end time.Time) {
+	if r.Start.IsZero() || start.Before(r.Start) {
+		r.Start = start
+	}
+	if end.After(r.End) {
+		r.End = end
+	}
+	r.Requested.Add(requested)
+	r.Limited.Add(limited)
+	r.Consumed.Add(consumed)
+}
+
+// Sampler samples the usage of the pods
+type Sampler interface {
+	PodUsage() ([]kube.PodUsage, error)
+}
+
+// Engine integrates the samples per pod and per task. The usage of a sample
+// is held since the pod was last integrated, or since it started, until now
+// or until it finished. Records of the deleted pods stay in their tasks.
+type Engine struct {
+	sync.Mutex
+	sampler Sampler
+	ledger  *Ledger
+	store   CheckpointStore
+
+	pods  map[string]*Record
+	tasks map[string]*Record
+}
+
+func NewEngine(sampler Sampler) *Engine {
+	return &Engine{
+		sampler: sampler,
+		pods:    map[string]*Record{},
+		tasks:   map[string]*Record{},
+	}
+}
+
+// SetLedger appends the interval records of every sample to the ledger
+func (e *Engine) SetLedger(ledger *Ledger) {
+	e.ledger = ledger
+}
+
+// SetCheckpointStore saves the records after every sample into the store
+func (e *Engine) SetCheckpointStore(store CheckpointStore) {
+	e.store = store
+}
+
+// Restore continues from the checkpoint in the store, the pods recorded are
+// integrated since their last sample instead of since they started
+func (e *Engine) Restore() error {
+	cp, err := e.store.Load()
+	if err != nil || cp == nil {
+		return err
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	e.pods = toPointers(cp.Pods)
+	e.tasks = toPointers(cp.Tasks)
+	return nil
+}
+
+// Sample integrates the usage of the pods until now, then appends the
+// interval records to the ledger and saves the checkpoint
+func (e *Engine) Sample(now time.Time) error {
+	pods, err := e.sampler.PodUsage()
+	if err != nil {
+		return err
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	entries := make([]Entry, 0, len(pods))
+	listed := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		key := pod.Namespace + "/" + pod.Name
+		listed[key] = true
+
+		start, end := pod.StartTime, now
+		if record, ok := e.pods[key]; ok && record.End.After(start) {
+			start = record.End
+		}
+		if !pod.FinishTime.IsZero() && pod.FinishTime.Before(end) {
+			end = pod.FinishTime
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		entry := Entry{Task: pod.Service, Pod: key}
+		entry.add(integrate(pod.Requested, end.Sub(start)), integrate(pod.Limited, end.Sub(start)),
+			integrate(pod.Consumed, end.Sub(start)), start, end)
+		entries = append(entries, entry)
+	}
+
+	// the ledger goes first, a crash before the checkpoint integrates the
+	// interval again and the ledger keeps both entries from the same pod
+	// start, the readers of the ledger keep the last one
+	if e.ledger != nil {
+		if err := e.ledger.Append(entries...); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if e.pods[entry.Pod] == nil {
+			e.pods[entry.Pod] = &Record{}
+		}
+		e.pods[entry.Pod].add(entry.Requested, entry.Limited, entry.Consumed, entry.Start, entry.End)
+		if e.tasks[entry.Task] == nil {
+			e.tasks[entry.Task] = &Record{}
+		}
+		e.tasks[entry.Task].add(entry.Requested, entry.Limited, entry.Consumed, entry.Start, entry.End)
+	}
+	for key := range e.pods {
+		if !listed[key] {
+			delete(e.pods, key)
+		}
+	}
+
+	if e.store != nil {
+		cp := &Checkpoint{Time: now, Pods: copyRecords(e.pods), Tasks: copyRecords(e.tasks)}
+		if err := e.store.Save(cp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run samples on every interval until stop is closed
+func (e *Engine) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := e.Sample(now); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Pods returns the records of the listed pods by namespace/name
+func (e *Engine) Pods() map[string]Record {
+	e.Lock()
+	defer e.Unlock()
+	return copyRecords(e.pods)
+}
+
+// Tasks returns the records of the tasks by manifest service name
+func (e *Engine) Tasks() map[string]Record {
+	e.Lock()
+	defer e.Unlock()
+	return copyRecords(e.tasks)
+}
+
+func copyRecords(records map[string]*Record) map[string]Record {
+	res := make(map[string]Record, len(records))
+	for key, record := range records {
+		res[key] = *record
+	}
+	return res
+}
+func toPointers(records map[string]Record) map[string]*Record {
+	res := make(map[string]*Record, len(records))
+	for key := range records {
+		record := records[key]
+		res[key] = &record
+	}
+	return res
+}
@@ -0,0 +1,130 @@
+package metering_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sampler []kube.PodUsage
+
+func (s *sampler) PodUsage() ([]kube.PodUsage, error) {
+	return *s, nil
+}
+
+func TestEngine_Sample(t *testing.T) {
+	start := time.Unix(1550000000, 0)
+	pod := func(name string) kube.PodUsage {
+		return kube.PodUsage{
+			Namespace: "default",
+			Name:      name,
+			Service:   "web",
+			StartTime: start,
+			Requested: kube.Metric{CPU: 100, Memory: 1 << 20, EphemeralStorage: 1 << 30},
+			Limited:   kube.Metric{CPU: 200, Memory: 1 << 20, EphemeralStorage: 1 << 30},
+			Consumed:  kube.Metric{CPU: 50, Memory: 1 << 19, EphemeralStorage: 1 << 30},
+		}
+	}
+	pods := &sampler{pod("web-1"), pod("web-2")}
+	engine := metering.NewEngine(pods)
+
+	require.NoError(t, engine.Sample(start.Add(10*time.Second)))
+	record := engine.Tasks()["web"]
+	assert.Equal(t, uint64(2*100*10), record.Requested.CPU)
+	assert.Equal(t, uint64(2*200*10), record.Limited.CPU)
+	assert.Equal(t, uint64(2*50*10), record.Consumed.CPU)
+	assert.Equal(t, uint64(2*(1<<19)*10), record.Consumed.Memory)
+	assert.Equal(t, start, record.Start)
+
+	// a replica stopped, its usage stays in the task
+	*pods = (*pods)[:1]
+	require.NoError(t, engine.Sample(start.Add(20*time.Second)))
+	record = engine.Tasks()["web"]
+	assert.Equal(t, uint64(2*100*10+100*10), record.Requested.CPU)
+	assert.Equal(t, uint64(3*(1<<30)*10), record.Requested.Disk)
+	assert.Equal(t, start.Add(20*time.Second), record.End)
+	assert.Len(t, engine.Pods(), 1)
+	assert.Equal(t, uint64(100*20), engine.Pods()["default/web-1"].Requested.CPU)
+}
+
+func TestEngine_Restore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	start := time.Unix(1550000000, 0)
+	pods := &sampler{{
+		Namespace:  "default",
+		Name:       "job-1",
+		Service:    "job",
+		StartTime:  start,
+		FinishTime: start.Add(15 * time.Second),
+		Requested:  kube.Metric{CPU: 100},
+	}}
+	ledger, err := metering.OpenLedger(filepath.Join(dir, "ledger"))
+	require.NoError(t, err)
+	engine := metering.NewEngine(pods)
+	engine.SetLedger(ledger)
+	engine.SetCheckpointStore(metering.NewFileStore(filepath.Join(dir, "checkpoint")))
+	require.NoError(t, engine.Sample(start.Add(10*time.Second)))
+	require.NoError(t, ledger.Close())
+
+	// the daemon restarted, the pod is integrated from the checkpoint until it finished
+	ledger, err = metering.OpenLedger(filepath.Join(dir, "ledger"))
+	require.NoError(t, err)
+	engine = metering.NewEngine(pods)
+	engine.SetLedger(ledger)
+	engine.SetCheckpointStore(metering.NewFileStore(filepath.Join(dir, "checkpoint")))
+	require.NoError(t, engine.Restore())
+	require.NoError(t, engine.Sample(start.Add(30*time.Second)))
+	require.NoError(t, engine.Sample(start.Add(40*time.Second)))
+	require.NoError(t, ledger.Close())
+	assert.Equal(t, uint64(100*15), engine.Tasks()["job"].Requested.CPU)
+	assert.Equal(t, start.Add(15*time.Second), engine.Tasks()["job"].End)
+
+	var seqs []uint64
+	require.NoError(t, metering.ReadLedger(filepath.Join(dir, "ledger"), func(entry *metering.Entry) error {
+		seqs = append(seqs, entry.Seq)
+		return nil
+	}))
+	assert.Equal(t, []uint64{1, 2}, seqs)
+}
+
+func TestOpenLedger_PartialLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ledger")
+	ledger, err := metering.OpenLedger(path)
+	require.NoError(t, err)
+	require.NoError(t, ledger.Append(metering.Entry{Task: "web", Pod: "default/web-1"}))
+	require.NoError(t, ledger.Close())
+
+	// a crash while appending the second entry
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"seq":2,"task":"we`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	ledger, err = metering.OpenLedger(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), ledger.Seq())
+	require.NoError(t, ledger.Append(metering.Entry{Task: "db", Pod: "default/db-1"}))
+	require.NoError(t, ledger.Close())
+
+	var tasks []string
+	require.NoError(t, metering.ReadLedger(path, func(entry *metering.Entry) error {
+		tasks = append(tasks, entry.Task)
+		return nil
+	}))
+	assert.Equal(t, []string{"web", "db"}, tasks)
+}
@@ -0,0 +1,271 @@
+package metering
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// Export formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// exportTime is the start of the period in the name of an export file
+const exportTime = "20060102T150405Z"
+
+// ExportRow is the usage of a pod of a task over a sample interval of the
+// ledger, in unit seconds, the part within the export period
+type ExportRow struct {
+	Seq     uint64    `json:"seq"`
+	Task    string    `json:"task"`
+	Pod     string    `json:"pod"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Seconds float64   `json:"seconds"`
+
+	Requested Usage `json:"requested"`
+	Limited   Usage `json:"limited"`
+	Consumed  Usage `json:"consumed"`
+}
+
+var exportHeader = []string{
+	"seq", "task", "pod", "start", "end", "seconds",
+	"cpu_requested", "memory_requested", "disk_requested",
+	"cpu_limited", "memory_limited", "disk_limited",
+	"cpu_consumed", "memory_consumed", "disk_consumed",
+}
+
+// Export writes a row per entry of the ledger overlapping the period, the
+// usage of an entry partly within the period is prorated. An interval
+// recorded twice is exported once. It returns the number of rows.
+func Export(path string, from, to time.Time, format string, w io.Writer) (int, error) {
+	var write func(row *ExportRow) error
+	var flush func() error
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(exportHeader)
+		write = func(row *ExportRow) error { return cw.Write(row.csv()) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(row *ExportRow) error { return enc.Encode(row) }
+		flush = func() error { return nil }
+	default:
+		return 0, errors.Errorf("unknown format: %s", format)
+	}
+
+	entries, err := readEntries(path, func(entry *Entry) bool {
+		return exportRow(entry, from, to) != nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, entry := range entries {
+		if err := write(exportRow(entry, from, to)); err != nil {
+			return i, errors.Wrap(err, "export row")
+		}
+	}
+	return len(entries), errors.Wrap(flush(), "export")
+}
+
+// exportRow is the part of the entry within the period, nil if none
+func exportRow(entry *Entry, from, to time.Time) *ExportRow {
+	start, end := entry.Start, entry.End
+	if !from.IsZero() && start.Before(from) {
+		start = from
+	}
+	if !to.IsZero() && end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return nil
+	}
+
+	part := end.Sub(start).Seconds() / entry.End.Sub(entry.Start).Seconds()
+	return &ExportRow{
+		Seq:       entry.Seq,
+		Task:      entry.Task,
+		Pod:       entry.Pod,
+		Start:     start,
+		End:       end,
+		Seconds:   end.Sub(start).Seconds(),
+		Requested: scale(entry.Requested, part),
+		Limited:   scale(entry.Limited, part),
+		Consumed:  scale(entry.Consumed, part),
+	}
+}
+
+func (row *ExportRow) csv() []string {
+	values := []string{
+		strconv.FormatUint(row.Seq, 10), row.Task, row.Pod,
+		row.Start.Format(time.RFC3339), row.End.Format(time.RFC3339),
+		strconv.FormatFloat(row.Seconds, 'f', 3, 64),
+	}
+	for _, usage := range []Usage{row.Requested, row.Limited, row.Consumed} {
+		values = append(values,
+			strconv.FormatUint(usage.CPU, 10),
+			strconv.FormatUint(usage.Memory, 10),
+			strconv.FormatUint(usage.Disk, 10))
+	}
+	return values
+}
+
+// Exporter writes the ledger into a file per period in the directory, once
+// the period is over and its last entries are appended. The oldest files
+// over Keep are removed, none if 0.
+type Exporter struct {
+	Ledger string
+	Dir    string
+	Format string
+	Period time.Duration
+	// Wait after the end of a period for the entries sampled then
+	Delay time.Duration
+	Keep  int
+}
+
+// FileName is the export file of the period starting at from
+func (e *Exporter) FileName(from time.Time) string {
+	return filepath.Join(e.Dir, "metering-"+from.UTC().Format(exportTime)+"."+e.Format)
+}
+
+// ExportPeriod writes the file of the period starting at from, the file
+// appears complete or not at all
+func (e *Exporter) ExportPeriod(from time.Time) (string, error) {
+	if err := os.MkdirAll(e.Dir, 0755); err != nil {
+		return "", errors.Wrap(err, "export")
+	}
+	name := e.FileName(from)
+	file, err := os.Create(name + ".tmp")
+	if err != nil {
+		return "", errors.Wrap(err, "export")
+	}
+	defer os.Remove(name + ".tmp")
+
+	_, err = Export(e.Ledger, from, from.Add(e.Period), e.Format, file)
+	if err := file.Close(); err != nil {
+		return "", errors.Wrap(err, "export")
+	}
+	if err != nil {
+		return "", err
+	}
+	return name, errors.Wrap(os.Rename(name+".tmp", name), "export")
+}
+
+// Run exports the periods over and not exported yet, then every period until
+// stop is closed
+func (e *Exporter) Run(stop <-chan struct{}) {
+	for {
+		now := time.Now()
+		last := now.Add(-e.Delay).Truncate(e.Period).Add(-e.Period)
+		// the end of the current period, or a retry of the failed export
+		wait := last.Add(2 * e.Period).Add(e.Delay).Sub(now)
+		if _, err := e.CatchUp(now); err != nil {
+			glog.Errorln("metering export:", err)
+			wait = time.Minute
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// CatchUp exports the periods over at now, from the one after the newest
+// export file, or from the first period of the ledger. It returns the names
+// of the files written.
+func (e *Exporter) CatchUp(now time.Time) ([]string, error) {
+	from, err := e.next()
+	if err != nil || from.IsZero() {
+		return nil, err
+	}
+
+	var names []string
+	last := now.Add(-e.Delay).Truncate(e.Period).Add(-e.Period)
+	for ; !from.After(last); from = from.Add(e.Period) {
+		name, err := e.ExportPeriod(from)
+		if err != nil {
+			return names, err
+		}
+		glog.Infoln("metering exported into", name)
+		names = append(names, name)
+		if err := e.rotate(); err != nil {
+			return names, err
+		}
+	}
+	return names, nil
+}
+
+// next is the start of the period after the newest export file, or of the
+// first period of the ledger, zero if the ledger is empty
+func (e *Exporter) next() (time.Time, error) {
+	names, err := e.files()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(names) > 0 {
+		newest := strings.TrimPrefix(filepath.Base(names[len(names)-1]), "metering-")
+		from, err := time.Parse(exportTime, strings.TrimSuffix(newest, "."+e.Format))
+		if err != nil {
+			return time.Time{}, errors.Wrap(err, "export file")
+		}
+		return from.Add(e.Period), nil
+	}
+
+	var first time.Time
+	err = ReadLedger(e.Ledger, func(entry *Entry) error {
+		first = entry.Start
+		return io.EOF
+	})
+	if err != nil && err != io.EOF {
+		return time.Time{}, err
+	}
+	if first.IsZero() {
+		return first, nil
+	}
+	return first.Truncate(e.Period), nil
+}
+
+// files returns the export files, sorted by the start of their periods
+func (e *Exporter) files() ([]string, error) {
+	names, err := filepath.Glob(filepath.Join(e.Dir, "metering-*."+e.Format))
+	if err != nil {
+		return nil, errors.Wrap(err, "list exports")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// rotate removes the oldest export files over Keep
+func (e *Exporter) rotate() error {
+	if e.Keep <= 0 {
+		return nil
+	}
+	names, err := e.files()
+	if err != nil {
+		return err
+	}
+	for len(names) > e.Keep {
+		if err := os.Remove(names[0]); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "rotate exports")
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package metering_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	ledger, err := metering.OpenLedger(filepath.Join(dir, "ledger"))
+	require.NoError(t, err)
+	require.NoError(t, ledger.Append(
+		// half of the entry is before the period
+		metering.Entry{Task: "web", Pod: "default/web-1", Record: metering.Record{
+			Requested: metering.Usage{CPU: 1000},
+			Consumed:  metering.Usage{CPU: 400},
+			Start:     from.Add(-30 * time.Second),
+			End:       from.Add(30 * time.Second),
+		}},
+		metering.Entry{Task: "db", Pod: "default/db-1", Record: metering.Record{
+			Requested: metering.Usage{Memory: 300},
+			Start:     from.Add(30 * time.Second),
+			End:       from.Add(60 * time.Second),
+		}},
+		metering.Entry{Task: "db", Pod: "default/db-1", Record: metering.Record{
+			Start: from.Add(time.Hour),
+			End:   from.Add(time.Hour + 30*time.Second),
+		}},
+		// integrated again after a crash
+		metering.Entry{Task: "db", Pod: "default/db-1", Record: metering.Record{
+			Start: from.Add(time.Hour),
+			End:   from.Add(time.Hour + 40*time.Second),
+		}},
+	))
+	require.NoError(t, ledger.Close())
+
+	buf := &bytes.Buffer{}
+	rows, err := metering.Export(filepath.Join(dir, "ledger"), from, from.Add(time.Hour), metering.FormatCSV, buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "seq,task,pod,start,end,seconds,"))
+	assert.Equal(t, "1,web,default/web-1,2019-02-01T00:00:00Z,2019-02-01T00:00:30Z,30.000,500,0,0,0,0,0,200,0,0", lines[1])
+
+	buf.Reset()
+	rows, err = metering.Export(filepath.Join(dir, "ledger"), time.Time{}, time.Time{}, metering.FormatJSONL, buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	row := metering.ExportRow{}
+	require.NoError(t, json.NewDecoder(buf).Decode(&row))
+	assert.Equal(t, uint64(1000), row.Requested.CPU)
+	assert.Equal(t, 60.0, row.Seconds)
+
+	_, err = metering.Export(filepath.Join(dir, "ledger"), from, from, "parquet", buf)
+	assert.Error(t, err)
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	ledger, err := metering.OpenLedger(filepath.Join(dir, "ledger"))
+	require.NoError(t, err)
+	require.NoError(t, ledger.Append(metering.Entry{Task: "web", Pod: "default/web-1", Record: metering.Record{
+		Requested: metering.Usage{CPU: 1000},
+		Start:     from,
+		End:       from.Add(30 * time.Second),
+	}}))
+	require.NoError(t, ledger.Close())
+
+	exporter := &metering.Exporter{
+		Ledger: filepath.Join(dir, "ledger"),
+		Dir:    filepath.Join(dir, "exports"),
+		Format: metering.FormatJSONL,
+		Period: time.Hour,
+	}
+	name, err := exporter.ExportPeriod(from)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "exports", "metering-20190201T000000Z.jsonl"), name)
+
+	file, err := os.Open(name)
+	require.NoError(t, err)
+	defer file.Close()
+	count := 0
+	for s := bufio.NewScanner(file); s.Scan(); {
+		count++
+	}
+	assert.Equal(t, 1, count)
+
+	// no temporary file is left
+	names, err := filepath.Glob(filepath.Join(dir, "exports", "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{name}, names)
+
+	// the periods after the newest file are exported
+	names, err = exporter.CatchUp(from.Add(3*time.Hour + time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "exports", "metering-20190201T010000Z.jsonl"),
+		filepath.Join(dir, "exports", "metering-20190201T020000Z.jsonl"),
+	}, names)
+
+	// from the first period of the ledger without files
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "exports")))
+	names, err = exporter.CatchUp(from.Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{name, filepath.Join(dir, "exports", "metering-20190201T010000Z.jsonl")}, names)
+}
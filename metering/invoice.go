@@ -0,0 +1,202 @@
+package metering
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+)
+
+// LineItem is the usage of the pods fitting a resource group of the order,
+// priced per unit hour
+type LineItem struct {
+	// Index of the group in the order, -1 for the pods fitting none
+	Group int                `json:"group"`
+	Unit  types.ResourceUnit `json:"unit"`
+	Count uint32             `json:"count"`
+	Price uint64             `json:"price"`
+
+	// Unit hours used by the pods and ordered over the period
+	Hours        float64 `json:"hours"`
+	OrderedHours float64 `json:"orderedHours"`
+	Amount       uint64  `json:"amount"`
+	// More unit hours used than ordered, or pods fitting no group
+	Overused bool `json:"overused"`
+
+	Requested Usage `json:"requested"`
+	Limited   Usage `json:"limited"`
+	Consumed  Usage `json:"consumed"`
+}
+
+// Invoice is the statement of a deployment over a billing period
+type Invoice struct {
+	Deployment string      `json:"deployment"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Items      []*LineItem `json:"items"`
+	Total      uint64      `json:"total"`
+	Overused   bool        `json:"overused"`
+
+	order     []types.ResourceGroup
+	unmatched *LineItem
+}
+
+// NewInvoice creates the invoice of the deployment from the ordered resource
+// groups, the line items are in the order of the groups
+func NewInvoice(deployment string, from, to time.Time, order []types.ResourceGroup) *Invoice {
+	inv := &Invoice{
+		Deployment: deployment,
+		From:       from,
+		To:         to,
+		order:      order,
+		unmatched:  &LineItem{Group: -1},
+	}
+
+	hours := to.Sub(from).Hours()
+	for i, group := range order {
+		inv.Items = append(inv.Items, &LineItem{
+			Group:        i,
+			Unit:         group.Unit,
+			Count:        group.Count,
+			Price:        group.Price,
+			OrderedHours: float64(group.Count) * hours,
+		})
+	}
+	return inv
+}
+
+// Add bills the part of the ledger entry within the period, the pod is
+// billed by the cheapest group its limits fit in, its requests in the entries
+// without limits. The entries of the deployments of a multiple image task,
+// and of its canary, are billed to the task.
+func (inv *Invoice) Add(entry *Entry) {
+	if !billed(inv.Deployment, entry.Task) {
+		return
+	}
+
+	start, end := entry.Start, entry.End
+	if start.Before(inv.From) {
+		start = inv.From
+	}
+	if end.After(inv.To) {
+		end = inv.To
+	}
+	if !end.After(start) {
+		return
+	}
+
+	// usage of the entry is held evenly over its interval
+	seconds := entry.End.Sub(entry.Start).Seconds()
+	part := end.Sub(start).Seconds() / seconds
+	limited := entry.Limited
+	if limited == (Usage{}) {
+		limited = entry.Requested
+	}
+	pod := &types.ResourceUnit{
+		CPU:    uint32(math.Round(float64(limited.CPU) / seconds)),
+		Memory: uint64(math.Round(float64(limited.Memory) / seconds)),
+		Disk:   uint64(math.Round(float64(limited.Disk) / seconds)),
+	}
+
+	item := inv.unmatched
+	for i := range inv.order {
+		group := &inv.order[i]
+		if group.Unit.Fits(pod) && (item == inv.unmatched || group.Price < item.Price) {
+			item = inv.Items[i]
+		}
+	}
+
+	item.Hours += end.Sub(start).Hours()
+	item.Requested.Add(scale(entry.Requested, part))
+	item.Limited.Add(scale(entry.Limited, part))
+	item.Consumed.Add(scale(entry.Consumed, part))
+}
+
+// billed reports whether the service is billed to the task
+func billed(task, service string) bool {
+	return types.IsTaskService(task, service) || service == kube.CanaryName(task)
+}
+
+func scale(u Usage, part float64) Usage {
+	return Usage{
+		CPU:    uint64(float64(u.CPU) * part),
+		Memory: uint64(float64(u.Memory) * part),
+		Disk:   uint64(float64(u.Disk) * part),
+	}
+}
+
+// Close prices the line items and flags the over-usage, the pods fitting no
+// group are the last item
+func (inv *Invoice) Close() {
+	if inv.unmatched.Hours > 0 {
+		inv.Items = append(inv.Items, inv.unmatched)
+		inv.unmatched = &LineItem{Group: -1}
+	}
+
+	inv.Total = 0
+	inv.Overused = false
+	for _, item := range inv.Items {
+		item.Amount = uint64(math.Round(item.Hours * float64(item.Price)))
+		item.Overused = item.Group < 0 || item.Hours > item.OrderedHours
+		inv.Total += item.Amount
+		inv.Overused = inv.Overused || item.Overused
+	}
+}
+
+// NewLedgerInvoice bills the entries of the ledger, an interval recorded
+// twice is billed once
+func NewLedgerInvoice(path, deployment string, from, to time.Time, order []types.ResourceGroup) (*Invoice, error) {
+	inv := NewInvoice(deployment, from, to, order)
+	entries, err := readEntries(path, func(entry *Entry) bool {
+		return billed(deployment, entry.Task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		inv.Add(entry)
+	}
+	inv.Close()
+	return inv, nil
+}
+
+var invoiceHeader = []string{
+	"deployment", "from", "to", "group", "cpu", "memory", "disk", "count", "price",
+	"hours", "ordered_hours", "amount", "overused",
+}
+
+// WriteCSV writes the line items one per row, then the total
+func (inv *Invoice) WriteCSV(w io.Writer) error {
+	from, to := inv.From.Format(time.RFC3339), inv.To.Format(time.RFC3339)
+	formatHours := func(h float64) string { return strconv.FormatFloat(h, 'f', 3, 64) }
+
+	cw := csv.NewWriter(w)
+	cw.Write(invoiceHeader)
+	for _, item := range inv.Items {
+		cw.Write([]string{
+			inv.Deployment, from, to,
+			strconv.Itoa(item.Group),
+			strconv.FormatUint(uint64(item.Unit.CPU), 10),
+			strconv.FormatUint(item.Unit.Memory, 10),
+			strconv.FormatUint(item.Unit.Disk, 10),
+			strconv.FormatUint(uint64(item.Count), 10),
+			strconv.FormatUint(item.Price, 10),
+			formatHours(item.Hours),
+			formatHours(item.OrderedHours),
+			strconv.FormatUint(item.Amount, 10),
+			strconv.FormatBool(item.Overused),
+		})
+	}
+	cw.Write([]string{
+		inv.Deployment, from, to, "total", "", "", "", "", "", "", "",
+		strconv.FormatUint(inv.Total, 10),
+		strconv.FormatBool(inv.Overused),
+	})
+	cw.Flush()
+	return cw.Error()
+}
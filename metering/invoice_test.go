@@ -0,0 +1,124 @@
+package metering_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/metering"
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/Ankr-network/dccn-daemon/types/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoice(t *testing.T) {
+	from := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	small := types.ResourceUnit{CPU: unit.Core / 2, Memory: unit.Gi, Disk: unit.Gi}
+	large := types.ResourceUnit{CPU: 2 * unit.Core, Memory: 4 * unit.Gi, Disk: 10 * unit.Gi}
+	inv := metering.NewInvoice("web", from, from.Add(10*time.Hour), []types.ResourceGroup{
+		{Unit: large, Count: 1, Price: 40},
+		{Unit: small, Count: 1, Price: 10},
+	})
+
+	entry := func(task string, cpu uint64, start time.Time, d time.Duration) *metering.Entry {
+		seconds := uint64(d.Seconds())
+		return &metering.Entry{Task: task, Record: metering.Record{
+			Requested: metering.Usage{CPU: cpu * seconds, Memory: unit.Gi * seconds},
+			Start:     start,
+			End:       start.Add(d),
+		}}
+	}
+	// half of the entry is before the period
+	inv.Add(entry("web", unit.Core/2, from.Add(-2*time.Hour), 4*time.Hour))
+	inv.Add(entry("web", unit.Core/2, from.Add(2*time.Hour), 10*time.Hour))
+	inv.Add(entry("web", unit.Core, from, time.Hour))
+	inv.Add(entry("web", 4*unit.Core, from, time.Hour))
+	inv.Add(entry("db", unit.Core, from, time.Hour))
+	inv.Close()
+
+	require.Len(t, inv.Items, 3)
+	assert.Equal(t, 1.0, inv.Items[0].Hours)
+	assert.Equal(t, uint64(40), inv.Items[0].Amount)
+	assert.False(t, inv.Items[0].Overused)
+
+	// the small unit is the cheapest fitting, ordered for 10 hours
+	assert.Equal(t, 10.0, inv.Items[1].Hours)
+	assert.Equal(t, uint64(100), inv.Items[1].Amount)
+	assert.Equal(t, uint64(unit.Core/2*10*3600), inv.Items[1].Requested.CPU)
+	assert.False(t, inv.Items[1].Overused)
+
+	// fits no group
+	assert.Equal(t, -1, inv.Items[2].Group)
+	assert.True(t, inv.Items[2].Overused)
+	assert.Equal(t, uint64(140), inv.Total)
+	assert.True(t, inv.Overused)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, inv.WriteCSV(buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "web,2019-02-01T00:00:00Z,2019-02-01T10:00:00Z,total,,,,,,,,140,true", lines[4])
+}
+
+func TestInvoice_Canary(t *testing.T) {
+	from := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	small := types.ResourceUnit{CPU: unit.Core / 2, Memory: unit.Gi, Disk: unit.Gi}
+	inv := metering.NewInvoice("web", from, from.Add(10*time.Hour), []types.ResourceGroup{
+		{Unit: small, Count: 2, Price: 10},
+	})
+
+	for _, task := range []string{"web", kube.CanaryName("web"), "web-1", "web-canary-1", "webapp"} {
+		inv.Add(&metering.Entry{Task: task, Record: metering.Record{
+			Requested: metering.Usage{CPU: unit.Core / 2 * 3600, Memory: unit.Gi * 3600},
+			Start:     from,
+			End:       from.Add(time.Hour),
+		}})
+	}
+	inv.Close()
+
+	// the task, its canary and its deployment of a multiple image task
+	require.Len(t, inv.Items, 1)
+	assert.Equal(t, 3.0, inv.Items[0].Hours)
+	assert.Equal(t, uint64(30), inv.Total)
+}
+
+func TestNewLedgerInvoice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	small := types.ResourceUnit{CPU: unit.Core / 2, Memory: unit.Gi, Disk: unit.Gi}
+	entry := func(task, pod string, start time.Time, d time.Duration) metering.Entry {
+		seconds := uint64(d.Seconds())
+		return metering.Entry{Task: task, Pod: pod, Record: metering.Record{
+			Requested: metering.Usage{CPU: unit.Core / 2 * seconds},
+			Start:     start,
+			End:       start.Add(d),
+		}}
+	}
+
+	ledger, err := metering.OpenLedger(filepath.Join(dir, "ledger"))
+	require.NoError(t, err)
+	require.NoError(t, ledger.Append(
+		entry("web-0", "default/web-0-a", from, time.Hour),
+		entry("web-1", "default/web-1-a", from, time.Hour),
+		entry("webapp", "default/webapp-a", from, time.Hour),
+		// integrated again after a crash before the checkpoint
+		entry("web-0", "default/web-0-a", from, 2*time.Hour),
+	))
+	require.NoError(t, ledger.Close())
+
+	inv, err := metering.NewLedgerInvoice(filepath.Join(dir, "ledger"), "web", from, from.Add(10*time.Hour),
+		[]types.ResourceGroup{{Unit: small, Count: 2, Price: 10}})
+	require.NoError(t, err)
+	require.Len(t, inv.Items, 1)
+	assert.Equal(t, 3.0, inv.Items[0].Hours)
+	assert.Equal(t, uint64(30), inv.Total)
+}
@@ -0,0 +1,159 @@
+package metering
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Entry is the usage of a pod over a sample interval
+type Entry struct {
+	// Sequence number in the ledger, from 1
+	Seq  uint64 `json:"seq"`
+	Task string `json:"task"`
+	// namespace/name of the pod
+	Pod string `json:"pod"`
+	Record
+}
+
+// Ledger is the append-only file of the metering entries, one JSON entry
+// per line
+type Ledger struct {
+	sync.Mutex
+	file *os.File
+	seq  uint64
+}
+
+// OpenLedger opens or creates the ledger, the sequence continues from the
+// last entry. A truncated last line is cut off, the entries are appended
+// after the last complete one.
+func OpenLedger(path string) (*Ledger, error) {
+	var seq uint64
+	size, err := readLedger(path, func(entry *Entry) error {
+		seq = entry.Seq
+		return nil
+	})
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "open ledger")
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "open ledger")
+	}
+	return &Ledger{file: file, seq: seq}, nil
+}
+
+// Append numbers the entries and writes them to the disk
+func (l *Ledger) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	w := bufio.NewWriter(l.file)
+	enc := json.NewEncoder(w)
+	seq := l.seq
+	for i := range entries {
+		seq++
+		entries[i].Seq = seq
+		if err := enc.Encode(&entries[i]); err != nil {
+			return errors.Wrap(err, "append ledger")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "append ledger")
+	}
+	if err := l.file.Sync(); err != nil {
+		return errors.Wrap(err, "sync ledger")
+	}
+
+	l.seq = seq
+	return nil
+}
+
+// Seq returns the sequence number of the last entry
+func (l *Ledger) Seq() uint64 {
+	l.Lock()
+	defer l.Unlock()
+	return l.seq
+}
+
+func (l *Ledger) Close() error {
+	return l.file.Close()
+}
+
+// ReadLedger calls fn with the entries of the ledger in order. A truncated
+// last line, left by a crash while appending, is ignored.
+func ReadLedger(path string, fn func(entry *Entry) error) error {
+	_, err := readLedger(path, fn)
+	return err
+}
+
+// readLedger returns the size of the complete lines read
+func readLedger(path string, fn func(entry *Entry) error) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "read ledger")
+	}
+	defer file.Close()
+
+	var size int64
+	var seq uint64
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return size, nil
+		} else if err != nil {
+			return size, errors.Wrap(err, "read ledger")
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(line, entry); err != nil {
+			return size, errors.Wrapf(err, "ledger entry after %d", seq)
+		}
+		if err := fn(entry); err != nil {
+			return size, err
+		}
+		size += int64(len(line))
+		seq = entry.Seq
+	}
+}
+
+// readEntries returns the entries of the ledger kept by the filter, in
+// order. An interval integrated again after a crash, from the same start of
+// the pod, replaces the first entry.
+func readEntries(path string, keep func(entry *Entry) bool) ([]*Entry, error) {
+	type key struct {
+		pod   string
+		start time.Time
+	}
+	var entries []*Entry
+	index := map[key]int{}
+	err := ReadLedger(path, func(entry *Entry) error {
+		if !keep(entry) {
+			return nil
+		}
+		k := key{entry.Pod, entry.Start.UTC()}
+		if i, ok := index[k]; ok {
+			entries[i] = entry
+			return nil
+		}
+		index[k] = len(entries)
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
@@ -0,0 +1,12 @@
+package metering
+
+import "time"
+
+// Report is the metering of the tasks of a datacenter namespace, the payload
+// of the metering txs
+type Report struct {
+	DataCenter string            `json:"dataCenter"`
+	Namespace  string            `json:"namespace"`
+	Time       time.Time         `json:"time"`
+	Tasks      map[string]Record `json:"tasks"`
+}
@@ -0,0 +1,119 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/api/extensions/v1beta1"
+)
+
+// Canary runs the image beside the deployment of the task and routes the
+// weight percent of the ingress traffic to it. Calling it again replaces
+// the image and weight of the running canary.
+func (t *Tasker) Canary(name, image string, weight int, options *types.ServiceOptions) error {
+	if weight < 0 || weight > 100 {
+		return errors.Errorf("invalid canary weight: %d", weight)
+	}
+	options = t.secure(options)
+
+	ingresses := &extv1.IngressList{}
+	if err := kube.NewIngress(t.ns, &types.ManifestService{}, nil, nil).List(t.client, ingresses); err != nil {
+		return err
+	}
+	var main *extv1.Ingress
+	for i := range ingresses.Items {
+		if ingresses.Items[i].Name == name {
+			main = &ingresses.Items[i]
+		}
+	}
+	if main == nil {
+		return errors.Errorf("canary of %s: no ingress", name)
+	}
+
+	// the canary serves the ports of the main service
+	services := &corev1.ServiceList{}
+	if err := kube.NewService(t.ns, &types.ManifestService{}).List(t.client, services); err != nil {
+		return err
+	}
+	var exposes []*types.ManifestServiceExpose
+	for _, item := range services.Items {
+		if item.Name != name {
+			continue
+		}
+		for _, port := range item.Spec.Ports {
+			exposes = append(exposes, &types.ManifestServiceExpose{
+				Port:         uint32(port.TargetPort.IntValue()),
+				ExternalPort: uint32(port.Port),
+				Proto:        string(port.Protocol),
+			})
+		}
+	}
+	if len(exposes) == 0 {
+		return errors.Errorf("canary of %s: no service", name)
+	}
+
+	images := []string{image}
+	if options.GetPod() != nil || t.multiImageMode == MultiImagePod {
+		images, options = t.podImages(strings.Split(image, ","), options)
+	}
+	canary := types.NewManifestService(kube.CanaryName(name), images[0])
+	canary.Expose = exposes
+	options = withoutAutoscale(options)
+	if err := t.validate(canary, options, images[0]); err != nil {
+		return err
+	}
+
+	kubes := []kube.Kube{
+		kube.NewDeployment(t.ns, canary, options),
+		kube.NewService(t.ns, canary),
+		kube.NewCanaryIngress(t.ns, canary, main, weight),
+	}
+	if err := t.updateOrCreate(kubes); err != nil {
+		return err
+	}
+	return t.waitReady(canary.Name)
+}
+
+// Promote rolls the canary pod template out to the deployment of the task, and
+// removes the canary once the deployment is ready
+func (t *Tasker) Promote(name string) error {
+	service := &types.ManifestService{Name: name}
+	if err := t.updateOrCreate([]kube.Kube{kube.NewPromotion(t.ns, service)}); err != nil {
+		return err
+	}
+	if err := t.waitReady(name); err != nil {
+		return err
+	}
+	return t.AbortCanary(name)
+}
+
+// AbortCanary removes the canary of the task, all the traffic goes back to
+// the deployment of the task
+func (t *Tasker) AbortCanary(name string) error {
+	canary := &types.ManifestService{Name: kube.CanaryName(name)}
+	kubes := []kube.Kube{
+		kube.NewCanaryIngress(t.ns, canary, nil, 0),
+		kube.NewService(t.ns, canary),
+		kube.NewDeployment(t.ns, canary, nil),
+	}
+	for _, k := range kubes {
+		if err := k.Delete(t.client); err != nil && !kube.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutAutoscale keeps the canary at the fixed replicas
+func withoutAutoscale(options *types.ServiceOptions) *types.ServiceOptions {
+	if options.GetAutoscale() == nil {
+		return options
+	}
+
+	res := *options
+	res.Autoscale = nil
+	return &res
+}
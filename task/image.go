@@ -0,0 +1,146 @@
+package task
+
+import (
+	"path"
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ImagePolicy is the images the tasks of the datacenter may run. Patterns
+// are path.Match patterns on the repository with the registry, e.g.
+// docker.io/library/* or gcr.io/*/*. The zero policy allows any image.
+type ImagePolicy struct {
+	// Repositories allowed, any if empty
+	Allow []string `json:"allow,omitempty"`
+	// Repositories denied, checked before the allowed ones
+	Deny []string `json:"deny,omitempty"`
+
+	DenyLatest    bool `json:"denyLatest,omitempty"`
+	RequireDigest bool `json:"requireDigest,omitempty"`
+	// Max size in bytes of the images already pulled by a node, images no
+	// node has pulled are not checked
+	MaxSize int64 `json:"maxSize,omitempty"`
+}
+
+// imageRef is a parsed image reference
+type imageRef struct {
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseImage parses the image reference, images without registry are on
+// docker hub, whose official images are in library, and images without tag
+// or digest are latest
+func parseImage(image string) imageRef {
+	ref := imageRef{}
+	if i := strings.Index(image, "@"); i >= 0 {
+		image, ref.digest = image[:i], image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, ref.tag = image[:i], image[i+1:]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+
+	parts := strings.SplitN(image, "/", 2)
+	switch {
+	case len(parts) == 1:
+		parts = []string{"docker.io", image}
+	case parts[0] == "index.docker.io":
+		parts[0] = "docker.io"
+	case !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost":
+		parts = []string{"docker.io", image}
+	}
+	if parts[0] == "docker.io" && !strings.Contains(parts[1], "/") {
+		parts[1] = "library/" + parts[1]
+	}
+	ref.repository = parts[0] + "/" + parts[1]
+	return ref
+}
+
+// String is the normalized reference
+func (r imageRef) String() string {
+	s := r.repository
+	if r.tag != "" {
+		s += ":" + r.tag
+	}
+	if r.digest != "" {
+		s += "@" + r.digest
+	}
+	return s
+}
+
+func matchAny(patterns []string, repository string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repository); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Check rejects the image if not allowed, sizes is the size of the images
+// pulled by the nodes indexed by the normalized reference
+func (p *ImagePolicy) Check(image string, sizes map[string]int64) error {
+	ref := parseImage(image)
+	if pattern, ok := matchAny(p.Deny, ref.repository); ok {
+		return errors.Errorf("image policy: %s denied by %s", image, pattern)
+	}
+	if _, ok := matchAny(p.Allow, ref.repository); len(p.Allow) != 0 && !ok {
+		return errors.Errorf("image policy: %s not in the allowed repositories", image)
+	}
+	if p.DenyLatest && ref.tag == "latest" && ref.digest == "" {
+		return errors.Errorf("image policy: %s uses the latest tag", image)
+	}
+	if p.RequireDigest && ref.digest == "" {
+		return errors.Errorf("image policy: %s not pinned by digest", image)
+	}
+	if size, ok := sizes[ref.String()]; ok && p.MaxSize > 0 && size > p.MaxSize {
+		return errors.Errorf("image policy: %s of %d bytes exceeds %d", image, size, p.MaxSize)
+	}
+	return nil
+}
+
+// SetImagePolicy sets the images the tasks may run, the tasks violating it
+// are rejected before any object is created
+func (t *Tasker) SetImagePolicy(policy *ImagePolicy) {
+	t.imagePolicy = policy
+}
+
+// checkImages checks the images and the init and sidecar images of the
+// options against the image policy
+func (t *Tasker) checkImages(options *types.ServiceOptions, images ...string) error {
+	all := append([]string{}, images...)
+	all = append(all, options.GetPod().GetInit()...)
+	all = append(all, options.GetPod().GetSidecars()...)
+
+	// the image lists of the nodes, the same the metrics count
+	var sizes map[string]int64
+	if t.imagePolicy.MaxSize > 0 {
+		nodes := &corev1.NodeList{}
+		if err := kube.NewNode(t.ns, &types.ManifestService{}).List(t.client, nodes); err != nil {
+			return err
+		}
+		sizes = map[string]int64{}
+		for _, node := range nodes.Items {
+			for _, image := range node.Status.Images {
+				for _, name := range image.Names {
+					sizes[parseImage(name).String()] = image.SizeBytes
+				}
+			}
+		}
+	}
+
+	for _, image := range all {
+		if err := t.imagePolicy.Check(image, sizes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
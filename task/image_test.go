@@ -0,0 +1,43 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/dccn-daemon/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagePolicy_Check(t *testing.T) {
+	policy := &task.ImagePolicy{
+		Allow:      []string{"docker.io/library/*", "gcr.io/*/*"},
+		Deny:       []string{"docker.io/library/busybox"},
+		DenyLatest: true,
+		MaxSize:    100,
+	}
+	sizes := map[string]int64{
+		"docker.io/library/nginx:1.12": 50,
+		"docker.io/library/redis:5":    200,
+	}
+
+	assert.NoError(t, policy.Check("nginx:1.12", sizes))
+	assert.NoError(t, policy.Check("gcr.io/project/app:v1", sizes))
+	assert.NoError(t, policy.Check("nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31", sizes))
+
+	// the docker hub names of the official images
+	assert.NoError(t, policy.Check("docker.io/nginx:1.12", sizes))
+	assert.NoError(t, policy.Check("index.docker.io/library/nginx:1.12", sizes))
+	assert.NoError(t, policy.Check("index.docker.io/nginx:1.12", sizes))
+	assert.Error(t, policy.Check("docker.io/redis:5", sizes), "too large")
+
+	assert.Error(t, policy.Check("busybox:1.30", sizes), "denied")
+	assert.Error(t, policy.Check("docker.io/busybox:1.30", sizes), "denied")
+	assert.Error(t, policy.Check("index.docker.io/busybox:1.30", sizes), "denied")
+	assert.Error(t, policy.Check("quay.io/coreos/etcd:v3.3", sizes), "not allowed")
+	assert.Error(t, policy.Check("nginx", sizes), "implicit latest")
+	assert.Error(t, policy.Check("nginx:latest", sizes), "latest")
+	assert.Error(t, policy.Check("redis:5", sizes), "too large")
+
+	policy = &task.ImagePolicy{RequireDigest: true}
+	assert.Error(t, policy.Check("localhost:5000/app:v1", nil))
+	assert.NoError(t, policy.Check("localhost:5000/app@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31", nil))
+}
@@ -0,0 +1,108 @@
+package kube
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AttributeLabelPrefix marks the node labels advertised as provider
+// attributes, e.g. attribute.ankr.network/pool=gpu. Node pools reserved for
+// some requirement are tainted with the same key and value.
+const AttributeLabelPrefix = "attribute.ankr.network/"
+
+// provider attributes read from the well-known node labels
+var wellKnownAttributes = map[string]string{
+	"region":        "failure-domain.beta.kubernetes.io/region",
+	"zone":          "failure-domain.beta.kubernetes.io/zone",
+	"arch":          "beta.kubernetes.io/arch",
+	"os":            "beta.kubernetes.io/os",
+	"instance-type": "beta.kubernetes.io/instance-type",
+}
+
+// AttributeLabel is the node label of the provider attribute
+func AttributeLabel(name string) string {
+	if label, ok := wellKnownAttributes[name]; ok {
+		return label
+	}
+	return AttributeLabelPrefix + name
+}
+
+// NodeAttributes returns the provider attributes of the node labels, sorted
+// by name and value
+func NodeAttributes(labels map[string]string) []types.ProviderAttribute {
+	attrs := []types.ProviderAttribute{}
+	for name, label := range wellKnownAttributes {
+		if value, ok := labels[label]; ok {
+			attrs = append(attrs, types.ProviderAttribute{Name: name, Value: value})
+		}
+	}
+	for label, value := range labels {
+		if strings.HasPrefix(label, AttributeLabelPrefix) {
+			attrs = append(attrs, types.ProviderAttribute{
+				Name:  strings.TrimPrefix(label, AttributeLabelPrefix),
+				Value: value,
+			})
+		}
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].Name != attrs[j].Name {
+			return attrs[i].Name < attrs[j].Name
+		}
+		return attrs[i].Value < attrs[j].Value
+	})
+	return attrs
+}
+
+// placement maps the requirements to the pod scheduling constraints. A
+// requirement of one value selects the nodes labeled with it, a comma
+// separated list of values selects the nodes labeled with any of them. The
+// pods tolerate the taints of the required values.
+func placement(requirements []types.ProviderAttribute) (map[string]string, *corev1.Affinity, []corev1.Toleration) {
+	if len(requirements) == 0 {
+		return nil, nil, nil
+	}
+
+	selector := map[string]string{}
+	var expressions []corev1.NodeSelectorRequirement
+	var tolerations []corev1.Toleration
+	for _, requirement := range requirements {
+		label := AttributeLabel(requirement.Name)
+		values := strings.Split(requirement.Value, ",")
+		if len(values) == 1 {
+			selector[label] = values[0]
+		} else {
+			expressions = append(expressions, corev1.NodeSelectorRequirement{
+				Key:      label,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   values,
+			})
+		}
+
+		for _, value := range values {
+			tolerations = append(tolerations, corev1.Toleration{
+				Key:      label,
+				Operator: corev1.TolerationOpEqual,
+				Value:    value,
+			})
+		}
+	}
+
+	var affinity *corev1.Affinity
+	if len(expressions) != 0 {
+		affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: expressions}},
+				},
+			},
+		}
+	}
+	if len(selector) == 0 {
+		selector = nil
+	}
+	return selector, affinity, tolerations
+}
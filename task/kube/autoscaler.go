@@ -0,0 +1,151 @@
+package kube
+
+import (
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type autoscaler struct {
+	*common
+
+	*autoscalingv2.HorizontalPodAutoscaler
+}
+
+// NewAutoscaler scales the deployment of the service, the utilization is
+// measured by the metrics server against the container requests
+func NewAutoscaler(namespace string, service *types.ManifestService, options *types.ServiceOptions) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &autoscaler{
+		common: &common{
+			namespace: namespace,
+			service:   service,
+			options:   options,
+		},
+	}
+}
+
+func (k *autoscaler) spec() autoscalingv2.HorizontalPodAutoscalerSpec {
+	options := k.options.GetAutoscale()
+	min := int32(options.Min)
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       k.name(),
+		},
+		MinReplicas: &min,
+		MaxReplicas: int32(options.Max),
+	}
+
+	// the utilization is of the requests, the targets of the units
+	cpuBurst, memoryBurst := k.options.GetResources().Burst()
+	targets := []struct {
+		name   corev1.ResourceName
+		target int32
+		burst  float64
+	}{
+		{corev1.ResourceCPU, options.CPU, cpuBurst},
+		{corev1.ResourceMemory, options.Memory, memoryBurst},
+	}
+	for _, target := range targets {
+		if target.target <= 0 {
+			continue
+		}
+		utilization := int32(float64(target.target) * target.burst)
+		spec.Metrics = append(spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:                     target.name,
+				TargetAverageUtilization: &utilization,
+			},
+		})
+	}
+	return spec
+}
+
+func (k *autoscaler) build() {
+	k.HorizontalPodAutoscaler = &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   k.name(),
+			Labels: k.podLabels(),
+		},
+		Spec: k.spec(),
+	}
+}
+
+func (k *autoscaler) Create(c *Client) error {
+	k.build()
+	if c.versions.Autoscaler == AutoscalerV2 {
+		return errors.Wrap(k.createV2(c), "create autoscaler")
+	}
+
+	_, err := c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).Create(k.HorizontalPodAutoscaler)
+	return errors.Wrap(err, "create autoscaler")
+}
+
+func (k *autoscaler) Update(c *Client) (rollback func(c *Client) error, err error) {
+	defer func() { err = errors.Wrap(err, "update autoscaler") }()
+
+	if c.versions.Autoscaler == AutoscalerV2 {
+		return k.updateV2(c)
+	}
+
+	obj, err := c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.HorizontalPodAutoscaler = obj.DeepCopy()
+	k.HorizontalPodAutoscaler.Labels = k.podLabels()
+	k.HorizontalPodAutoscaler.Spec = k.spec()
+
+	_, err = c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).Update(k.HorizontalPodAutoscaler)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err = c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).Update(obj)
+		return err
+	}, nil
+}
+func (k *autoscaler) Delete(c *Client) error {
+	var err error
+	if c.versions.Autoscaler == AutoscalerV2 {
+		err = c.dync.Resource(autoscalerV2Resource).Namespace(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	} else {
+		err = c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	}
+	return errors.Wrap(err, "delete autoscaler")
+}
+func (k *autoscaler) DeleteCollection(c *Client, selector metav1.ListOptions) error {
+	var err error
+	if c.versions.Autoscaler == AutoscalerV2 {
+		err = c.dync.Resource(autoscalerV2Resource).Namespace(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	} else {
+		err = c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	}
+	return errors.Wrap(err, "delete autoscaler collection")
+}
+
+// List always returns autoscaling/v2beta1 autoscalers, autoscaling/v2 ones
+// are converted back
+func (k *autoscaler) List(c *Client, result interface{}) error {
+	if c.versions.Autoscaler == AutoscalerV2 {
+		return errors.Wrap(k.listV2(c, result.(*autoscalingv2.HorizontalPodAutoscalerList)), "list autoscaler")
+	}
+
+	list, err := c.AutoscalingV2beta1().HorizontalPodAutoscalers(k.ns()).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "list autoscaler")
+	}
+
+	*(result.(*autoscalingv2.HorizontalPodAutoscalerList)) = *list
+	return nil
+}
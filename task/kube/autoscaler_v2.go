@@ -0,0 +1,146 @@
+package kube
+
+import (
+	"github.com/pkg/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// autoscaling/v2 horizontal pod autoscaler, only the fields the daemon
+// manages
+type autoscalerV2 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec autoscalerV2Spec `json:"spec"`
+}
+type autoscalerV2Spec struct {
+	ScaleTargetRef autoscalingv2.CrossVersionObjectReference `json:"scaleTargetRef"`
+	MinReplicas    *int32                                    `json:"minReplicas,omitempty"`
+	MaxReplicas    int32                                     `json:"maxReplicas"`
+	Metrics        []autoscalerV2Metric                      `json:"metrics,omitempty"`
+}
+type autoscalerV2Metric struct {
+	Type     autoscalingv2.MetricSourceType `json:"type"`
+	Resource *autoscalerV2ResourceMetric    `json:"resource,omitempty"`
+}
+type autoscalerV2ResourceMetric struct {
+	Name   corev1.ResourceName `json:"name"`
+	Target autoscalerV2Target  `json:"target"`
+}
+type autoscalerV2Target struct {
+	Type               string `json:"type"`
+	AverageUtilization *int32 `json:"averageUtilization,omitempty"`
+}
+
+// utilizationTarget is the target type of the utilization in percent of the
+// requests
+const utilizationTarget = "Utilization"
+
+func toAutoscalerV2(obj *autoscalingv2.HorizontalPodAutoscaler) *autoscalerV2 {
+	res := &autoscalerV2{
+		TypeMeta:   metav1.TypeMeta{APIVersion: AutoscalerV2, Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: *obj.ObjectMeta.DeepCopy(),
+		Spec: autoscalerV2Spec{
+			ScaleTargetRef: obj.Spec.ScaleTargetRef,
+			MinReplicas:    obj.Spec.MinReplicas,
+			MaxReplicas:    obj.Spec.MaxReplicas,
+		},
+	}
+	for _, metric := range obj.Spec.Metrics {
+		if metric.Resource == nil {
+			continue
+		}
+		res.Spec.Metrics = append(res.Spec.Metrics, autoscalerV2Metric{
+			Type: metric.Type,
+			Resource: &autoscalerV2ResourceMetric{
+				Name: metric.Resource.Name,
+				Target: autoscalerV2Target{
+					Type:               utilizationTarget,
+					AverageUtilization: metric.Resource.TargetAverageUtilization,
+				},
+			},
+		})
+	}
+	return res
+}
+func fromAutoscalerV2(obj *autoscalerV2) *autoscalingv2.HorizontalPodAutoscaler {
+	res := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: *obj.ObjectMeta.DeepCopy(),
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: obj.Spec.ScaleTargetRef,
+			MinReplicas:    obj.Spec.MinReplicas,
+			MaxReplicas:    obj.Spec.MaxReplicas,
+		},
+	}
+	for _, metric := range obj.Spec.Metrics {
+		if metric.Resource == nil || metric.Resource.Target.Type != utilizationTarget {
+			continue
+		}
+		res.Spec.Metrics = append(res.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: metric.Type,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:                     metric.Resource.Name,
+				TargetAverageUtilization: metric.Resource.Target.AverageUtilization,
+			},
+		})
+	}
+	return res
+}
+
+func (k *autoscaler) createV2(c *Client) error {
+	obj, err := toUnstructured(toAutoscalerV2(k.HorizontalPodAutoscaler), AutoscalerV2, "HorizontalPodAutoscaler")
+	if err != nil {
+		return err
+	}
+
+	_, err = c.dync.Resource(autoscalerV2Resource).Namespace(k.ns()).Create(obj, metav1.CreateOptions{})
+	return err
+}
+
+func (k *autoscaler) updateV2(c *Client) (rollback func(c *Client) error, err error) {
+	client := c.dync.Resource(autoscalerV2Resource).Namespace(k.ns())
+	obj, err := client.Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.build()
+	desired, err := toUnstructured(toAutoscalerV2(k.HorizontalPodAutoscaler), AutoscalerV2, "HorizontalPodAutoscaler")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateUnstructured(obj, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = client.Update(updated, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err := c.dync.Resource(autoscalerV2Resource).Namespace(k.ns()).Update(obj, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+func (k *autoscaler) listV2(c *Client, result *autoscalingv2.HorizontalPodAutoscalerList) error {
+	list, err := c.dync.Resource(autoscalerV2Resource).Namespace(k.ns()).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	res := autoscalingv2.HorizontalPodAutoscalerList{}
+	for i := range list.Items {
+		obj := &autoscalerV2{}
+		if err := fromUnstructured(&list.Items[i], obj); err != nil {
+			return errors.Wrapf(err, "autoscaler %s", list.Items[i].GetName())
+		}
+		res.Items = append(res.Items, *fromAutoscalerV2(obj))
+	}
+
+	*result = res
+	return nil
+}
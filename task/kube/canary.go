@@ -0,0 +1,231 @@
+package kube
+
+import (
+	"strconv"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// annotations of the nginx ingress controller splitting the traffic of the
+// hosts of the main ingress to the canary one
+const (
+	canaryAnnotation       = "nginx.ingress.kubernetes.io/canary"
+	canaryWeightAnnotation = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// CanaryName is the name of the canary deployment, service and ingress of
+// the manifest service
+func CanaryName(name string) string {
+	return name + "-canary"
+}
+
+type canaryIngress struct {
+	*ingress
+	main   *extv1.Ingress
+	weight int
+}
+
+// NewCanaryIngress routes the weight percent of the traffic of the main
+// ingress to the service, which is the canary of the main one
+func NewCanaryIngress(namespace string, service *types.ManifestService, main *extv1.Ingress, weight int) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &canaryIngress{
+		ingress: &ingress{
+			common: &common{
+				namespace: namespace,
+				service:   service,
+			},
+		},
+		main:   main,
+		weight: weight,
+	}
+}
+
+func (k *canaryIngress) build(c *Client) {
+	spec := k.main.Spec.DeepCopy()
+	if spec.Backend != nil {
+		spec.Backend.ServiceName = k.name()
+	}
+	for i := range spec.Rules {
+		if spec.Rules[i].HTTP == nil {
+			continue
+		}
+		for j := range spec.Rules[i].HTTP.Paths {
+			spec.Rules[i].HTTP.Paths[j].Backend.ServiceName = k.name()
+		}
+	}
+
+	k.Ingress = &extv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        k.name(),
+			Labels:      k.labels(),
+			Annotations: k.annotations(c),
+		},
+		Spec: *spec,
+	}
+}
+
+func (k *canaryIngress) annotations(c *Client) map[string]string {
+	annotations := map[string]string{
+		canaryAnnotation:       "true",
+		canaryWeightAnnotation: strconv.Itoa(k.weight),
+	}
+	// networking.k8s.io/v1 ingresses select the controller by their class
+	// name, the api server rejects them with the annotation too
+	if c.ingressClass != "" && c.versions.Ingress != IngressNetworkingV1 {
+		annotations[ingressClassAnnotation] = c.ingressClass
+	}
+	return annotations
+}
+
+func (k *canaryIngress) Create(c *Client) error {
+	k.build(c)
+	if c.versions.Ingress == IngressNetworkingV1 {
+		return errors.Wrap(k.createV1(c), "create canary ingress")
+	}
+
+	_, err := c.ExtensionsV1beta1().Ingresses(k.ns()).Create(k.Ingress)
+	return errors.Wrap(err, "create canary ingress")
+}
+
+func (k *canaryIngress) Update(c *Client) (rollback func(c *Client) error, err error) {
+	defer func() { err = errors.Wrap(err, "update canary ingress") }()
+
+	if c.versions.Ingress == IngressNetworkingV1 {
+		return k.updateCanaryV1(c)
+	}
+
+	obj, err := c.ExtensionsV1beta1().Ingresses(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.build(c)
+	desired := k.Ingress
+	k.Ingress = obj.DeepCopy()
+	k.Ingress.Labels = desired.Labels
+	k.Ingress.Annotations = desired.Annotations
+	k.Ingress.Spec = desired.Spec
+
+	_, err = c.ExtensionsV1beta1().Ingresses(k.ns()).Update(k.Ingress)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err = c.ExtensionsV1beta1().Ingresses(k.ns()).Update(obj)
+		return err
+	}, nil
+}
+
+func (k *canaryIngress) updateCanaryV1(c *Client) (rollback func(c *Client) error, err error) {
+	client := c.dync.Resource(ingressV1Resource).Namespace(k.ns())
+	obj, err := client.Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.build(c)
+	desired, err := toUnstructured(toIngressV1(k.Ingress, c.ingressClass), IngressNetworkingV1, "Ingress")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateUnstructured(obj, desired)
+	if err != nil {
+		return nil, err
+	}
+	updated.SetAnnotations(k.Ingress.Annotations)
+
+	if _, err = client.Update(updated, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err := c.dync.Resource(ingressV1Resource).Namespace(k.ns()).Update(obj, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+type promotion struct {
+	*common
+}
+
+// NewPromotion rolls the pod template of the canary deployment out to the
+// main deployment of the service, it is applied by Update only
+func NewPromotion(namespace string, service *types.ManifestService) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &promotion{
+		common: &common{
+			namespace: namespace,
+			service:   service,
+		},
+	}
+}
+
+func (k *promotion) Create(c *Client) error {
+	return errors.Errorf("promote %s: no deployment", k.name())
+}
+
+func (k *promotion) Update(c *Client) (rollback func(c *Client) error, err error) {
+	defer func() { err = errors.Wrap(err, "promote canary") }()
+
+	canary, err := c.AppsV1().Deployments(k.ns()).Get(CanaryName(k.name()), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.AppsV1().Deployments(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := obj.DeepCopy()
+	promote(promoted, canary)
+	if _, err = c.AppsV1().Deployments(k.ns()).Update(promoted); err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err = c.AppsV1().Deployments(k.ns()).Update(obj)
+		return err
+	}, nil
+}
+
+// promote copies the pod template of the canary, its containers, volumes,
+// scheduling and annotations, into the deployment. The labels selecting the
+// pods and the names of the containers in the same position are kept.
+func promote(obj, canary *appsv1.Deployment) {
+	template := canary.Spec.Template.DeepCopy()
+	template.Labels = obj.Spec.Template.Labels
+	keepNames(template.Spec.InitContainers, obj.Spec.Template.Spec.InitContainers)
+	keepNames(template.Spec.Containers, obj.Spec.Template.Spec.Containers)
+	obj.Spec.Template = *template
+}
+
+func keepNames(containers, names []corev1.Container) {
+	for i := range containers {
+		if i < len(names) {
+			containers[i].Name = names[i].Name
+		}
+	}
+}
+
+func (k *promotion) Delete(c *Client) error {
+	return nil
+}
+func (k *promotion) DeleteCollection(c *Client, selector metav1.ListOptions) error {
+	return nil
+}
+func (k *promotion) List(c *Client, result interface{}) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package kube
+
+import (
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type configMap struct {
+	*common
+	data map[string]string
+
+	*corev1.ConfigMap
+}
+
+// NewConfigMap writes the state of the daemon into the config map
+func NewConfigMap(namespace, name string, data map[string]string) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &configMap{
+		common: &common{
+			namespace: namespace,
+			service:   &types.ManifestService{Name: name},
+		},
+		data: data,
+	}
+}
+
+func (k *configMap) Create(c *Client) error {
+	k.ConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   k.name(),
+			Labels: k.labels(),
+		},
+		Data: k.data,
+	}
+	_, err := c.CoreV1().ConfigMaps(k.ns()).Create(k.ConfigMap)
+	return errors.Wrap(err, "create configmap")
+}
+
+func (k *configMap) Update(c *Client) (rollback func(c *Client) error, err error) {
+	defer func() { err = errors.Wrap(err, "update configmap") }()
+
+	obj, err := c.CoreV1().ConfigMaps(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.ConfigMap = obj.DeepCopy()
+	k.ConfigMap.Labels = k.labels()
+	k.ConfigMap.Data = k.data
+
+	_, err = c.CoreV1().ConfigMaps(k.ns()).Update(k.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err = c.CoreV1().ConfigMaps(k.ns()).Update(obj)
+		return err
+	}, nil
+}
+func (k *configMap) Delete(c *Client) error {
+	err := c.CoreV1().ConfigMaps(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	return errors.Wrap(err, "delete configmap")
+}
+func (k *configMap) DeleteCollection(c *Client, selector metav1.ListOptions) error {
+	err := c.CoreV1().ConfigMaps(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	return errors.Wrap(err, "delete configmap collection")
+}
+
+func (k *configMap) List(c *Client, result interface{}) error {
+	list, err := c.CoreV1().ConfigMaps(k.ns()).List(metav1.ListOptions{
+		LabelSelector: Selector(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "list configmap")
+	}
+
+	*(result.(*corev1.ConfigMapList)) = *list
+	return nil
+}
@@ -17,7 +17,8 @@ type cronJob struct {
 	*batchv1beta1.CronJob
 }
 
-func NewCronJob(namespace string, service *types.ManifestService, schedule string) Kube {
+func NewCronJob(namespace string, service *types.ManifestService, options *types.ServiceOptions,
+	schedule string) Kube {
 	if mockKube != nil {
 		return mockKube
 	}
@@ -26,6 +27,7 @@ func NewCronJob(namespace string, service *types.ManifestService, schedule strin
 		common: &common{
 			namespace: namespace,
 			service:   service,
+			options:   options,
 		},
 		service:  service,
 		schedule: schedule,
@@ -50,11 +52,10 @@ func (k *cronJob) build() {
 					},
 					Template: corev1.PodTemplateSpec{
 						ObjectMeta: metav1.ObjectMeta{
-							Labels: k.labels(),
-						},
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{k.container()},
+							Labels:      k.podLabels(),
+							Annotations: k.podAnnotations(),
 						},
+						Spec: k.podSpec(),
 					},
 				},
 			},
@@ -64,6 +65,15 @@ func (k *cronJob) build() {
 
 func (k *cronJob) Create(c *Client) error {
 	k.build()
+	if c.versions.CronJob == CronJobBatchV1 {
+		obj, err := toUnstructured(k.CronJob, CronJobBatchV1, "CronJob")
+		if err != nil {
+			return errors.Wrap(err, "create job")
+		}
+		_, err = c.dync.Resource(cronJobV1Resource).Namespace(k.ns()).Create(obj, metav1.CreateOptions{})
+		return errors.Wrap(err, "create job")
+	}
+
 	_, err := c.BatchV1beta1().CronJobs(k.ns()).Create(k.CronJob)
 	return errors.Wrap(err, "create job")
 }
@@ -71,13 +81,20 @@ func (k *cronJob) Create(c *Client) error {
 func (k *cronJob) Update(c *Client) (rollback func(c *Client) error, err error) {
 	defer func() { err = errors.Wrap(err, "update job") }()
 
+	if c.versions.CronJob == CronJobBatchV1 {
+		return k.updateV1(c)
+	}
+
 	obj, err := c.BatchV1beta1().CronJobs(k.ns()).Get(k.name(), metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
+	k.build()
+	desired := k.CronJob
 	k.CronJob = obj.DeepCopy()
-	k.CronJob.Labels = k.labels()
+	k.CronJob.Labels = desired.Labels
+	k.CronJob.Spec = desired.Spec
 
 	_, err = c.BatchV1beta1().CronJobs(k.ns()).Update(k.CronJob)
 	if err != nil {
@@ -89,16 +106,74 @@ func (k *cronJob) Update(c *Client) (rollback func(c *Client) error, err error)
 		return err
 	}, nil
 }
+
+// updateV1 replaces the labels, schedule and job template of the batch/v1
+// object, the fields batch/v1beta1 lacks, such as timeZone, are kept
+func (k *cronJob) updateV1(c *Client) (rollback func(c *Client) error, err error) {
+	client := c.dync.Resource(cronJobV1Resource).Namespace(k.ns())
+	obj, err := client.Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.build()
+	desired, err := toUnstructured(k.CronJob, CronJobBatchV1, "CronJob")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateUnstructured(obj, desired, "schedule", "jobTemplate")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = client.Update(updated, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err := c.dync.Resource(cronJobV1Resource).Namespace(k.ns()).Update(obj, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
 func (k *cronJob) Delete(c *Client) error {
-	err := c.BatchV1beta1().CronJobs(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	var err error
+	if c.versions.CronJob == CronJobBatchV1 {
+		err = c.dync.Resource(cronJobV1Resource).Namespace(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	} else {
+		err = c.BatchV1beta1().CronJobs(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	}
 	return errors.Wrap(err, "delete job")
 }
 func (k *cronJob) DeleteCollection(c *Client, selector metav1.ListOptions) error {
-	err := c.BatchV1beta1().CronJobs(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	var err error
+	if c.versions.CronJob == CronJobBatchV1 {
+		err = c.dync.Resource(cronJobV1Resource).Namespace(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	} else {
+		err = c.BatchV1beta1().CronJobs(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	}
 	return errors.Wrap(err, "delete job collection")
 }
 
+// List always returns batch/v1beta1 cronjobs, batch/v1 ones are converted
 func (k *cronJob) List(c *Client, result interface{}) error {
+	if c.versions.CronJob == CronJobBatchV1 {
+		list, err := c.dync.Resource(cronJobV1Resource).Namespace(k.ns()).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "list cronJob")
+		}
+
+		res := batchv1beta1.CronJobList{}
+		for i := range list.Items {
+			item := batchv1beta1.CronJob{}
+			if err := fromUnstructured(&list.Items[i], &item); err != nil {
+				return errors.Wrap(err, "list cronJob")
+			}
+			res.Items = append(res.Items, item)
+		}
+		*(result.(*batchv1beta1.CronJobList)) = res
+		return nil
+	}
+
 	list, err := c.BatchV1beta1().CronJobs(k.ns()).List(metav1.ListOptions{})
 	if err != nil {
 		return errors.Wrap(err, "list cronJob")
@@ -6,6 +6,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type deployment struct {
@@ -15,7 +16,7 @@ type deployment struct {
 	*appsv1.Deployment
 }
 
-func NewDeployment(namespace string, service *types.ManifestService) Kube {
+func NewDeployment(namespace string, service *types.ManifestService, options *types.ServiceOptions) Kube {
 	if mockKube != nil {
 		return mockKube
 	}
@@ -24,30 +25,63 @@ func NewDeployment(namespace string, service *types.ManifestService) Kube {
 		common: &common{
 			namespace: namespace,
 			service:   service,
+			options:   options,
 		},
 		service: service,
 	}
 }
 
+// replicas starts autoscaled deployments at the minimum
+func (k *deployment) replicas() int32 {
+	if autoscale := k.options.GetAutoscale(); autoscale != nil {
+		return int32(autoscale.Min)
+	}
+	return int32(k.service.Count)
+}
+
+func (k *deployment) strategy() appsv1.DeploymentStrategy {
+	options := k.options.GetStrategy()
+	if options == nil {
+		return appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	}
+	if options.Type == types.StrategyRecreate {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	}
+
+	rollingUpdate := &appsv1.RollingUpdateDeployment{}
+	if options.MaxSurge != "" {
+		maxSurge := intstr.Parse(options.MaxSurge)
+		rollingUpdate.MaxSurge = &maxSurge
+	}
+	if options.MaxUnavailable != "" {
+		maxUnavailable := intstr.Parse(options.MaxUnavailable)
+		rollingUpdate.MaxUnavailable = &maxUnavailable
+	}
+	return appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: rollingUpdate,
+	}
+}
+
 func (k *deployment) build() {
-	replicas := int32(k.service.Count)
+	replicas := k.replicas()
 	k.Deployment = &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   k.name(),
-			Labels: k.labels(),
+			Labels: k.podLabels(),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: k.labels(),
+				MatchLabels: k.podLabels(),
 			},
 			Replicas: &replicas,
+			Strategy: k.strategy(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: k.labels(),
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{k.container()},
+					Labels:      k.podLabels(),
+					Annotations: k.podAnnotations(),
 				},
+				Spec: k.podSpec(),
 			},
 		},
 	}
@@ -68,12 +102,30 @@ func (k *deployment) Update(c *Client) (rollback func(c *Client) error, err erro
 	}
 
 	k.Deployment = obj.DeepCopy()
-	replicas := int32(k.service.Count)
-	k.Deployment.Labels = k.labels()
-	k.Deployment.Spec.Selector.MatchLabels = k.labels()
-	k.Deployment.Spec.Replicas = &replicas
-	k.Deployment.Spec.Template.Labels = k.labels()
-	k.Deployment.Spec.Template.Spec.Containers = []corev1.Container{k.container()}
+	k.Deployment.Labels = k.podLabels()
+	// selector is immutable, deployments created before the manifest service
+	// label keep selecting by the managed label only
+	// the replicas of autoscaled deployments are owned by the autoscaler
+	if k.options.GetAutoscale() == nil || obj.Spec.Replicas == nil {
+		replicas := k.replicas()
+		k.Deployment.Spec.Replicas = &replicas
+	}
+	k.Deployment.Spec.Template.Labels = k.podLabels()
+	if k.Deployment.Spec.Template.Annotations == nil {
+		k.Deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	for key, value := range k.podAnnotations() {
+		k.Deployment.Spec.Template.Annotations[key] = value
+	}
+	k.Deployment.Spec.Strategy = k.strategy()
+	spec := k.podSpec()
+	k.Deployment.Spec.Template.Spec.InitContainers = spec.InitContainers
+	k.Deployment.Spec.Template.Spec.Containers = spec.Containers
+	k.Deployment.Spec.Template.Spec.Volumes = spec.Volumes
+	k.Deployment.Spec.Template.Spec.TerminationGracePeriodSeconds = spec.TerminationGracePeriodSeconds
+	k.Deployment.Spec.Template.Spec.NodeSelector = spec.NodeSelector
+	k.Deployment.Spec.Template.Spec.Affinity = spec.Affinity
+	k.Deployment.Spec.Template.Spec.Tolerations = spec.Tolerations
 
 	_, err = c.AppsV1().Deployments(k.ns()).Update(k.Deployment)
 	if err != nil {
@@ -15,7 +15,8 @@ type ingress struct {
 	*extv1.Ingress
 }
 
-func NewIngress(namespace string, service *types.ManifestService, expose *types.ManifestServiceExpose) Kube {
+func NewIngress(namespace string, service *types.ManifestService, expose *types.ManifestServiceExpose,
+	options *types.ServiceOptions) Kube {
 	if mockKube != nil {
 		return mockKube
 	}
@@ -24,6 +25,7 @@ func NewIngress(namespace string, service *types.ManifestService, expose *types.
 		common: &common{
 			namespace: namespace,
 			service:   service,
+			options:   options,
 		},
 		expose: expose,
 	}
@@ -36,24 +38,88 @@ func (k *ingress) build() {
 			Labels: k.labels(),
 		},
 		Spec: extv1.IngressSpec{
-			Backend: &extv1.IngressBackend{
-				ServiceName: k.name(),
-				ServicePort: intstr.FromInt(int(exposeExternalPort(k.expose))),
-			},
-			Rules: k.rules(),
+			Backend: k.backend(k.expose),
+			Rules:   k.rules(),
+			TLS:     k.tls(),
 		},
 	}
 }
+func (k *ingress) backend(expose *types.ManifestServiceExpose) *extv1.IngressBackend {
+	return &extv1.IngressBackend{
+		ServiceName: k.name(),
+		ServicePort: intstr.FromInt(int(exposeExternalPort(expose))),
+	}
+}
+
+// paths routes every global HTTP expose with a configured path, the main
+// expose is served on "/" by default
+func (k *ingress) paths() []extv1.HTTPIngressPath {
+	paths := make([]extv1.HTTPIngressPath, 0, len(k.service.Expose))
+	for _, expose := range k.service.Expose {
+		if !expose.Global || !expose.IsHTTP() {
+			continue
+		}
+
+		path := k.options.Path(expose.Port)
+		if path == "" && expose.Port == k.expose.Port {
+			path = "/"
+		}
+		if path == "" {
+			continue
+		}
+		paths = append(paths, extv1.HTTPIngressPath{
+			Path:    path,
+			Backend: *k.backend(expose),
+		})
+	}
+	return paths
+}
 func (k *ingress) rules() []extv1.IngressRule {
+	paths := k.paths()
 	rules := make([]extv1.IngressRule, 0, len(k.expose.Hosts))
 	for _, host := range k.expose.Hosts {
-		rules = append(rules, extv1.IngressRule{Host: host})
+		rules = append(rules, extv1.IngressRule{
+			Host: host,
+			IngressRuleValue: extv1.IngressRuleValue{
+				HTTP: &extv1.HTTPIngressRuleValue{Paths: paths},
+			},
+		})
 	}
 	return rules
 }
+func (k *ingress) tls() []extv1.IngressTLS {
+	opts := k.options.GetTLS()
+	if opts == nil || len(k.expose.Hosts) == 0 {
+		return nil
+	}
+
+	secretName := opts.SecretName
+	if secretName == "" {
+		secretName = TLSSecretName(k.name())
+	}
+	return []extv1.IngressTLS{{
+		Hosts:      k.expose.Hosts,
+		SecretName: secretName,
+	}}
+}
+
+// ingressClassAnnotation selects the controller of extensions/v1beta1 ingresses
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+func (k *ingress) annotations(c *Client) map[string]string {
+	if c.ingressClass == "" {
+		return nil
+	}
+	return map[string]string{ingressClassAnnotation: c.ingressClass}
+}
 
 func (k *ingress) Create(c *Client) error {
 	k.build()
+	if c.versions.Ingress == IngressNetworkingV1 {
+		return errors.Wrap(k.createV1(c), "create ingress")
+	}
+
+	k.Ingress.Annotations = k.annotations(c)
 	_, err := c.ExtensionsV1beta1().Ingresses(k.ns()).Create(k.Ingress)
 	return errors.Wrap(err, "create ingress")
 }
@@ -61,6 +127,10 @@ func (k *ingress) Create(c *Client) error {
 func (k *ingress) Update(c *Client) (rollback func(c *Client) error, err error) {
 	defer func() { err = errors.Wrap(err, "update ingress") }()
 
+	if c.versions.Ingress == IngressNetworkingV1 {
+		return k.updateV1(c)
+	}
+
 	obj, err := c.ExtensionsV1beta1().Ingresses(k.ns()).Get(k.name(), metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -68,8 +138,15 @@ func (k *ingress) Update(c *Client) (rollback func(c *Client) error, err error)
 
 	k.Ingress = obj.DeepCopy()
 	k.Ingress.Labels = k.labels()
-	k.Ingress.Spec.Backend.ServicePort = intstr.FromInt(int(exposeExternalPort(k.expose)))
+	if c.ingressClass != "" {
+		if k.Ingress.Annotations == nil {
+			k.Ingress.Annotations = map[string]string{}
+		}
+		k.Ingress.Annotations[ingressClassAnnotation] = c.ingressClass
+	}
+	k.Ingress.Spec.Backend = k.backend(k.expose)
 	k.Ingress.Spec.Rules = k.rules()
+	k.Ingress.Spec.TLS = k.tls()
 
 	_, err = c.ExtensionsV1beta1().Ingresses(k.ns()).Update(k.Ingress)
 	if err != nil {
@@ -82,15 +159,31 @@ func (k *ingress) Update(c *Client) (rollback func(c *Client) error, err error)
 	}, nil
 }
 func (k *ingress) Delete(c *Client) error {
-	err := c.ExtensionsV1beta1().Ingresses(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	var err error
+	if c.versions.Ingress == IngressNetworkingV1 {
+		err = c.dync.Resource(ingressV1Resource).Namespace(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	} else {
+		err = c.ExtensionsV1beta1().Ingresses(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	}
 	return errors.Wrap(err, "delete ingress")
 }
 func (k *ingress) DeleteCollection(c *Client, selector metav1.ListOptions) error {
-	err := c.ExtensionsV1beta1().Ingresses(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	var err error
+	if c.versions.Ingress == IngressNetworkingV1 {
+		err = c.dync.Resource(ingressV1Resource).Namespace(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	} else {
+		err = c.ExtensionsV1beta1().Ingresses(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	}
 	return errors.Wrap(err, "delete ingress collection")
 }
 
+// List always returns extensions/v1beta1 ingresses, networking.k8s.io/v1
+// ones are converted back
 func (k *ingress) List(c *Client, result interface{}) error {
+	if c.versions.Ingress == IngressNetworkingV1 {
+		return errors.Wrap(k.listV1(c, result.(*extv1.IngressList)), "list ingress")
+	}
+
 	list, err := c.ExtensionsV1beta1().Ingresses(k.ns()).List(metav1.ListOptions{})
 	if err != nil {
 		return errors.Wrap(err, "list ingress")
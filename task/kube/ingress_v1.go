@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"github.com/pkg/errors"
+	extv1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// networking.k8s.io/v1 ingress, only the fields the daemon manages
+type ingressV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ingressV1Spec `json:"spec,omitempty"`
+}
+type ingressV1Spec struct {
+	IngressClassName *string            `json:"ingressClassName,omitempty"`
+	DefaultBackend   *ingressV1Backend  `json:"defaultBackend,omitempty"`
+	TLS              []extv1.IngressTLS `json:"tls,omitempty"`
+	Rules            []ingressV1Rule    `json:"rules,omitempty"`
+}
+type ingressV1Rule struct {
+	Host string         `json:"host,omitempty"`
+	HTTP *ingressV1HTTP `json:"http,omitempty"`
+}
+type ingressV1HTTP struct {
+	Paths []ingressV1Path `json:"paths"`
+}
+type ingressV1Path struct {
+	Path     string           `json:"path,omitempty"`
+	PathType string           `json:"pathType"`
+	Backend  ingressV1Backend `json:"backend"`
+}
+type ingressV1Backend struct {
+	Service *ingressV1ServiceBackend `json:"service,omitempty"`
+}
+type ingressV1ServiceBackend struct {
+	Name string        `json:"name"`
+	Port ingressV1Port `json:"port"`
+}
+type ingressV1Port struct {
+	Name   string `json:"name,omitempty"`
+	Number int32  `json:"number,omitempty"`
+}
+
+func toIngressV1Backend(backend *extv1.IngressBackend) *ingressV1Backend {
+	if backend == nil {
+		return nil
+	}
+
+	res := &ingressV1Backend{
+		Service: &ingressV1ServiceBackend{Name: backend.ServiceName},
+	}
+	if backend.ServicePort.Type == intstr.String {
+		res.Service.Port.Name = backend.ServicePort.StrVal
+	} else {
+		res.Service.Port.Number = backend.ServicePort.IntVal
+	}
+	return res
+}
+func fromIngressV1Backend(backend *ingressV1Backend) *extv1.IngressBackend {
+	if backend == nil || backend.Service == nil {
+		return nil
+	}
+
+	res := &extv1.IngressBackend{ServiceName: backend.Service.Name}
+	if backend.Service.Port.Name != "" {
+		res.ServicePort = intstr.FromString(backend.Service.Port.Name)
+	} else {
+		res.ServicePort = intstr.FromInt(int(backend.Service.Port.Number))
+	}
+	return res
+}
+
+func toIngressV1(obj *extv1.Ingress, class string) *ingressV1 {
+	res := &ingressV1{
+		TypeMeta:   metav1.TypeMeta{APIVersion: IngressNetworkingV1, Kind: "Ingress"},
+		ObjectMeta: *obj.ObjectMeta.DeepCopy(),
+	}
+	if class != "" {
+		res.Spec.IngressClassName = &class
+	}
+	res.Spec.DefaultBackend = toIngressV1Backend(obj.Spec.Backend)
+	res.Spec.TLS = obj.Spec.TLS
+
+	for _, rule := range obj.Spec.Rules {
+		ruleV1 := ingressV1Rule{Host: rule.Host}
+		if rule.HTTP != nil {
+			ruleV1.HTTP = &ingressV1HTTP{}
+			for _, path := range rule.HTTP.Paths {
+				ruleV1.HTTP.Paths = append(ruleV1.HTTP.Paths, ingressV1Path{
+					Path:     path.Path,
+					PathType: "Prefix",
+					Backend:  *toIngressV1Backend(&path.Backend),
+				})
+			}
+		}
+		res.Spec.Rules = append(res.Spec.Rules, ruleV1)
+	}
+	return res
+}
+func fromIngressV1(obj *ingressV1) *extv1.Ingress {
+	res := &extv1.Ingress{
+		ObjectMeta: *obj.ObjectMeta.DeepCopy(),
+	}
+	res.Spec.Backend = fromIngressV1Backend(obj.Spec.DefaultBackend)
+	res.Spec.TLS = obj.Spec.TLS
+
+	for _, rule := range obj.Spec.Rules {
+		ruleV1beta1 := extv1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			ruleV1beta1.HTTP = &extv1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				backend := fromIngressV1Backend(&path.Backend)
+				if backend == nil {
+					continue
+				}
+				ruleV1beta1.HTTP.Paths = append(ruleV1beta1.HTTP.Paths, extv1.HTTPIngressPath{
+					Path:    path.Path,
+					Backend: *backend,
+				})
+			}
+		}
+		res.Spec.Rules = append(res.Spec.Rules, ruleV1beta1)
+	}
+	return res
+}
+
+func (k *ingress) createV1(c *Client) error {
+	obj, err := toUnstructured(toIngressV1(k.Ingress, c.ingressClass), IngressNetworkingV1, "Ingress")
+	if err != nil {
+		return err
+	}
+
+	_, err = c.dync.Resource(ingressV1Resource).Namespace(k.ns()).Create(obj, metav1.CreateOptions{})
+	return err
+}
+
+func (k *ingress) updateV1(c *Client) (rollback func(c *Client) error, err error) {
+	client := c.dync.Resource(ingressV1Resource).Namespace(k.ns())
+	obj, err := client.Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.build()
+	desired, err := toUnstructured(toIngressV1(k.Ingress, c.ingressClass), IngressNetworkingV1, "Ingress")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateUnstructured(obj, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = client.Update(updated, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err := c.dync.Resource(ingressV1Resource).Namespace(k.ns()).Update(obj, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+func (k *ingress) listV1(c *Client, result *extv1.IngressList) error {
+	list, err := c.dync.Resource(ingressV1Resource).Namespace(k.ns()).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	res := extv1.IngressList{}
+	for i := range list.Items {
+		obj := &ingressV1{}
+		if err := fromUnstructured(&list.Items[i], obj); err != nil {
+			return errors.Wrapf(err, "ingress %s", list.Items[i].GetName())
+		}
+		res.Items = append(res.Items, *fromIngressV1(obj))
+	}
+
+	*result = res
+	return nil
+}
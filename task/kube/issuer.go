@@ -0,0 +1,138 @@
+package kube
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertIssuer provides the certificate of task ingresses. The returned Kube
+// writes the certificate into the secret named TLSSecretName(service.Name).
+type CertIssuer interface {
+	Issue(namespace string, service *types.ManifestService, hosts []string) (Kube, error)
+}
+
+type selfSignedIssuer struct {
+	validFor time.Duration
+}
+
+// NewSelfSignedIssuer signs the certificates with a throwaway key, only for
+// the datacenters running without a public CA
+func NewSelfSignedIssuer(validFor time.Duration) CertIssuer {
+	return &selfSignedIssuer{validFor: validFor}
+}
+
+func (i *selfSignedIssuer) Issue(namespace string, service *types.ManifestService, hosts []string) (Kube, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("issue certificate: no host")
+	}
+	if mockKube != nil {
+		return mockKube, nil
+	}
+
+	return &issuedSecret{
+		tlsSecret: &tlsSecret{common: &common{namespace: namespace, service: service}},
+		issuer:    i,
+		hosts:     hosts,
+	}, nil
+}
+
+// issue signs a certificate of the hosts
+func (i *selfSignedIssuer) issue(hosts []string) (cert, pkey []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "issue certificate: generate key")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "issue certificate: serial number")
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hosts[0]},
+		DNSNames:              hosts,
+		NotBefore:             now,
+		NotAfter:              now.Add(i.validFor),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "issue certificate: sign")
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "issue certificate: marshal key")
+	}
+
+	cert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pkey = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return cert, pkey, nil
+}
+
+// valid reports whether the certificate is of the hosts and valid for a
+// third of the validity of the issued ones at least
+func (i *selfSignedIssuer) valid(cert []byte, hosts []string) bool {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return false
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || time.Now().Add(i.validFor/3).After(parsed.NotAfter) {
+		return false
+	}
+
+	names := append([]string{}, parsed.DNSNames...)
+	wanted := append([]string{}, hosts...)
+	sort.Strings(names)
+	sort.Strings(wanted)
+	return reflect.DeepEqual(names, wanted)
+}
+
+// issuedSecret is the secret of a self-signed certificate, which is issued
+// again only if the certificate of the secret is not valid for the hosts
+type issuedSecret struct {
+	*tlsSecret
+	issuer *selfSignedIssuer
+	hosts  []string
+}
+
+func (k *issuedSecret) Create(c *Client) error {
+	if k.cert == nil {
+		var err error
+		if k.cert, k.key, err = k.issuer.issue(k.hosts); err != nil {
+			return err
+		}
+	}
+	return k.tlsSecret.Create(c)
+}
+
+func (k *issuedSecret) Update(c *Client) (rollback func(c *Client) error, err error) {
+	obj, err := c.CoreV1().Secrets(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "update secret")
+	}
+	if k.issuer.valid(obj.Data[corev1.TLSCertKey], k.hosts) {
+		return nil, nil
+	}
+
+	if k.cert, k.key, err = k.issuer.issue(k.hosts); err != nil {
+		return nil, err
+	}
+	return k.tlsSecret.Update(c)
+}
@@ -15,7 +15,7 @@ type job struct {
 	*batchv1.Job
 }
 
-func NewJob(namespace string, service *types.ManifestService) Kube {
+func NewJob(namespace string, service *types.ManifestService, options *types.ServiceOptions) Kube {
 	if mockKube != nil {
 		return mockKube
 	}
@@ -24,6 +24,7 @@ func NewJob(namespace string, service *types.ManifestService) Kube {
 		common: &common{
 			namespace: namespace,
 			service:   service,
+			options:   options,
 		},
 		service: service,
 	}
@@ -41,11 +42,10 @@ func (k *job) build() {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: k.labels(),
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{k.container()},
+					Labels:      k.podLabels(),
+					Annotations: k.podAnnotations(),
 				},
+				Spec: k.podSpec(),
 			},
 		},
 	}
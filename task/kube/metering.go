@@ -5,6 +5,8 @@ import (
 
 	"github.com/Ankr-network/dccn-daemon/types"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,43 +42,111 @@ func (k *metering) DeleteCollection(c *Client, selector metav1.ListOptions) erro
 	return nil
 }
 
-func (k *metering) List(c *Client, result interface{}) error {
-	list, err := c.CoreV1().Pods(k.ns()).List(metav1.ListOptions{
-		LabelSelector: Selector(),
-	})
+// PodUsage is a sample of the resources of a running pod, CPU in milli
+// cores and the others in bytes
+type PodUsage struct {
+	Namespace string
+	Name      string
+	Node      string
+	// Manifest service running the pod
+	Service   string
+	StartTime time.Time
+	// Time the last container of a succeeded or failed pod terminated, zero
+	// while the pod is running
+	FinishTime time.Time
+
+	Requested Metric
+	// Limits of the containers, above the requests of the burstable pods
+	Limited Metric
+	// Usage reported by the metrics server, zero until the first scrape of
+	// the pod. Storage is not reported and stays zero.
+	Consumed Metric
+}
+
+// List samples the usage of the running and terminated pods into *[]PodUsage
+func (k *metering) List(c *Client, result interface{}) (err error) {
+	defer func() { err = errors.Wrap(err, "list metering") }()
+
+	selector := metav1.ListOptions{LabelSelector: Selector()}
+	pods, err := c.CoreV1().Pods(k.ns()).List(selector)
+	if err != nil {
+		return err
+	}
+	metcs, err := c.metc.Metrics().PodMetricses(k.ns()).List(selector)
 	if err != nil {
-		return errors.Wrap(err, "list pods")
+		return err
 	}
 
-	now := time.Now()
-	res := &map[string]*types.ResourceUnit{}
-	for _, item := range list.Items {
-		var cpu, mem, disk int64
-		for _, status := range item.Status.ContainerStatuses {
-			if status.State.Running == nil {
-				continue
-			}
-
-			nano := int64(now.Sub(status.State.Running.StartedAt.Time).Seconds())
-
-			for _, container := range item.Spec.Containers {
-				if container.Name != status.Name {
-					continue
-				}
-
-				limits := container.Resources.Limits
-				cpu += nano * limits.Cpu().MilliValue() // unit: types.Core
-				mem += nano * limits.Memory().MilliValue() / 1000
-				disk += nano * limits.StorageEphemeral().MilliValue() / 1000
-			}
-			(*res)[status.Name] = &types.ResourceUnit{
-				CPU:    uint32(cpu),
-				Memory: uint64(mem),
-				Disk:   uint64(disk),
-			}
+	consumed := map[string]*Metric{}
+	for _, item := range metcs.Items {
+		metric := &Metric{}
+		for _, container := range item.Containers {
+			metric.CPU += container.Usage.Cpu().MilliValue()
+			metric.Memory += container.Usage.Memory().Value()
+		}
+		consumed[item.Namespace+"/"+item.Name] = metric
+	}
+
+	res := make([]PodUsage, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		if item.Status.StartTime == nil {
+			continue
+		}
+
+		usage := PodUsage{
+			Namespace: item.Namespace,
+			Name:      item.Name,
+			Service:   ManifestServiceName(&item),
+			Node:      item.Spec.NodeName,
+			StartTime: item.Status.StartTime.Time,
+		}
+		switch item.Status.Phase {
+		case corev1.PodRunning:
+		case corev1.PodSucceeded, corev1.PodFailed:
+			usage.FinishTime = finishTime(&item)
+		default:
+			continue
 		}
+		for _, container := range item.Spec.Containers {
+			usage.Requested.CPU += request(container, corev1.ResourceCPU).MilliValue()
+			usage.Requested.Memory += request(container, corev1.ResourceMemory).Value()
+			usage.Requested.EphemeralStorage += request(container, corev1.ResourceEphemeralStorage).Value()
+			usage.Limited.CPU += limit(container, corev1.ResourceCPU).MilliValue()
+			usage.Limited.Memory += limit(container, corev1.ResourceMemory).Value()
+			usage.Limited.EphemeralStorage += limit(container, corev1.ResourceEphemeralStorage).Value()
+		}
+		if metric, ok := consumed[item.Namespace+"/"+item.Name]; ok {
+			usage.Consumed = *metric
+		}
+
+		res = append(res, usage)
 	}
 
-	*(result.(*map[string]*types.ResourceUnit)) = *res
+	*(result.(*[]PodUsage)) = res
 	return nil
 }
+
+// request is the request of the container, which defaults to the limit
+func request(container corev1.Container, name corev1.ResourceName) *resource.Quantity {
+	if quantity, ok := container.Resources.Requests[name]; ok {
+		return &quantity
+	}
+	quantity := container.Resources.Limits[name]
+	return &quantity
+}
+
+func limit(container corev1.Container, name corev1.ResourceName) *resource.Quantity {
+	quantity := container.Resources.Limits[name]
+	return &quantity
+}
+
+// finishTime is the time the last container of the pod terminated
+func finishTime(pod *corev1.Pod) time.Time {
+	finish := pod.Status.StartTime.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finish) {
+			finish = terminated.FinishedAt.Time
+		}
+	}
+	return finish
+}
@@ -49,27 +49,52 @@ type Metric struct {
 }
 
 type Metrics struct {
-	NodeImages map[string][][]string
-	NodeTotal  map[string]*Metric
-	NodeInUse  map[string]*Metric
-	Endpoints  map[int32]int64
+	NodeAttributes map[string][]types.ProviderAttribute
+	NodeImages     map[string][][]string
+	NodeTotal      map[string]*Metric
+	NodeInUse      map[string]*Metric
+	Endpoints      map[int32]int64
+
+	// Resources requested by the pods scheduled on the nodes, of all
+	// namespaces
+	NodeRequested map[string]*Metric
+	// Pods the nodes can run, and the pods scheduled on them
+	NodePodCapacity map[string]int64
+	NodePods        map[string]int64
+	// Not cordoned and ready
+	NodeSchedulable map[string]bool
+	// Traffic of the nodes and of the pods by namespace/name, missing if the
+	// kubelet is not reachable
+	NodeNetwork map[string]*Network
+	PodNetwork  map[string]*Network
 }
 
 func (k *metrics) List(c *Client, result interface{}) (err error) {
 	defer func() { err = errors.Wrap(err, "list metrics") }()
 
 	res := &Metrics{
-		NodeImages: map[string][][]string{},
-		NodeTotal:  map[string]*Metric{},
-		NodeInUse:  map[string]*Metric{},
-		Endpoints:  map[int32]int64{},
+		NodeAttributes: map[string][]types.ProviderAttribute{},
+		NodeImages:     map[string][][]string{},
+		NodeTotal:      map[string]*Metric{},
+		NodeInUse:      map[string]*Metric{},
+		Endpoints:      map[int32]int64{},
+
+		NodeRequested:   map[string]*Metric{},
+		NodePodCapacity: map[string]int64{},
+		NodePods:        map[string]int64{},
+		NodeSchedulable: map[string]bool{},
+		NodeNetwork:     map[string]*Network{},
+		PodNetwork:      map[string]*Network{},
 	}
 	{
 		nodeList := &corev1.NodeList{}
 		if err := NewNode(k.ns(), k.service).List(c, nodeList); err != nil {
 			return err
 		}
+		names := make([]string, 0, len(nodeList.Items))
 		for _, item := range nodeList.Items {
+			res.NodeAttributes[item.Name] = NodeAttributes(item.Labels)
+
 			images := make([][]string, 0, len(nodeList.Items))
 			for _, image := range item.Status.Images {
 				images = append(images, image.Names)
@@ -87,9 +112,43 @@ func (k *metrics) List(c *Client, result interface{}) (err error) {
 					metric.Storage = quantity.MilliValue() / 1000
 				case corev1.ResourceEphemeralStorage:
 					metric.EphemeralStorage = quantity.MilliValue() / 1000
+				case corev1.ResourcePods:
+					res.NodePodCapacity[item.Name] = quantity.Value()
 				}
 			}
 			res.NodeTotal[item.Name] = metric
+			res.NodeRequested[item.Name] = &Metric{}
+			res.NodeSchedulable[item.Name] = !item.Spec.Unschedulable && nodeReady(&item)
+			names = append(names, item.Name)
+		}
+
+		for name, stats := range kubeletSummaries(c, names) {
+			res.NodeNetwork[name] = stats.Node.Network.network()
+			for _, pod := range stats.Pods {
+				res.PodNetwork[pod.PodRef.Namespace+"/"+pod.PodRef.Name] = pod.Network.network()
+			}
+		}
+	}
+
+	{
+		// the pods of all the namespaces take the capacity of the nodes
+		pods, err := c.CoreV1().Pods("").List(metav1.ListOptions{
+			FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+		})
+		if err != nil {
+			return errors.Wrap(err, "list pods")
+		}
+		for _, item := range pods.Items {
+			requested, ok := res.NodeRequested[item.Spec.NodeName]
+			if !ok {
+				continue
+			}
+			res.NodePods[item.Spec.NodeName]++
+			for _, container := range item.Spec.Containers {
+				requested.CPU += request(container, corev1.ResourceCPU).MilliValue()
+				requested.Memory += request(container, corev1.ResourceMemory).Value()
+				requested.EphemeralStorage += request(container, corev1.ResourceEphemeralStorage).Value()
+			}
 		}
 	}
 
@@ -132,3 +191,13 @@ func (k *metrics) List(c *Client, result interface{}) (err error) {
 	*(result.(*Metrics)) = *res
 	return nil
 }
+
+// nodeReady returns whether the node is ready to run pods
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package kube
+
+import (
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TLSSecretName is the name of the secret the daemon writes the task certificate
+func TLSSecretName(name string) string {
+	return name + "-tls"
+}
+
+type tlsSecret struct {
+	*common
+	cert []byte
+	key  []byte
+
+	*corev1.Secret
+}
+
+func NewTLSSecret(namespace string, service *types.ManifestService, cert, key []byte) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &tlsSecret{
+		common: &common{
+			namespace: namespace,
+			service:   service,
+		},
+		cert: cert,
+		key:  key,
+	}
+}
+
+func (k *tlsSecret) name() string {
+	return TLSSecretName(k.service.Name)
+}
+
+func (k *tlsSecret) build() {
+	k.Secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   k.name(),
+			Labels: k.labels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: k.data(),
+	}
+}
+func (k *tlsSecret) data() map[string][]byte {
+	return map[string][]byte{
+		corev1.TLSCertKey:       k.cert,
+		corev1.TLSPrivateKeyKey: k.key,
+	}
+}
+
+func (k *tlsSecret) Create(c *Client) error {
+	k.build()
+	_, err := c.CoreV1().Secrets(k.ns()).Create(k.Secret)
+	return errors.Wrap(err, "create secret")
+}
+
+func (k *tlsSecret) Update(c *Client) (rollback func(c *Client) error, err error) {
+	defer func() { err = errors.Wrap(err, "update secret") }()
+
+	obj, err := c.CoreV1().Secrets(k.ns()).Get(k.name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k.Secret = obj.DeepCopy()
+	k.Secret.Labels = k.labels()
+	k.Secret.Data = k.data()
+
+	_, err = c.CoreV1().Secrets(k.ns()).Update(k.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *Client) error {
+		_, err = c.CoreV1().Secrets(k.ns()).Update(obj)
+		return err
+	}, nil
+}
+func (k *tlsSecret) Delete(c *Client) error {
+	err := c.CoreV1().Secrets(k.ns()).Delete(k.name(), &metav1.DeleteOptions{})
+	return errors.Wrap(err, "delete secret")
+}
+func (k *tlsSecret) DeleteCollection(c *Client, selector metav1.ListOptions) error {
+	err := c.CoreV1().Secrets(k.ns()).DeleteCollection(&metav1.DeleteOptions{}, selector)
+	return errors.Wrap(err, "delete secret collection")
+}
+
+func (k *tlsSecret) List(c *Client, result interface{}) error {
+	list, err := c.CoreV1().Secrets(k.ns()).List(metav1.ListOptions{
+		LabelSelector: Selector(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "list secret")
+	}
+
+	*(result.(*corev1.SecretList)) = *list
+	return nil
+}
@@ -2,6 +2,7 @@ package kube
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/Ankr-network/dccn-daemon/types"
 	"github.com/pkg/errors"
@@ -10,14 +11,42 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// ExternalServiceName is the name of the service of a global TCP/UDP expose
+func ExternalServiceName(name string, expose *types.ManifestServiceExpose) string {
+	return name + "-" + strings.ToLower(string(expose.Protocol())) + "-" +
+		strconv.Itoa(int(exposeExternalPort(expose)))
+}
+
 type service struct {
 	*common
-	expose *types.ManifestServiceExpose
+	// global TCP/UDP expose served by the service, nil for the cluster ip
+	// service of all the exposes
+	expose      *types.ManifestServiceExpose
+	serviceType corev1.ServiceType
+	nodePort    int32
 
 	*corev1.Service
 }
 
-func NewService(namespace string, svc *types.ManifestService, expose *types.ManifestServiceExpose) Kube {
+// NewService creates the cluster ip service of all the exposes
+func NewService(namespace string, svc *types.ManifestService) Kube {
+	if mockKube != nil {
+		return mockKube
+	}
+
+	return &service{
+		common: &common{
+			namespace: namespace,
+			service:   svc,
+		},
+		serviceType: corev1.ServiceTypeClusterIP,
+	}
+}
+
+// NewExternalService creates the NodePort or LoadBalancer service of a
+// global TCP/UDP expose, the node port is allocated by the cluster if 0
+func NewExternalService(namespace string, svc *types.ManifestService, expose *types.ManifestServiceExpose,
+	serviceType corev1.ServiceType, nodePort int32) Kube {
 	if mockKube != nil {
 		return mockKube
 	}
@@ -27,30 +56,57 @@ func NewService(namespace string, svc *types.ManifestService, expose *types.Mani
 			namespace: namespace,
 			service:   svc,
 		},
-		expose: expose,
+		expose:      expose,
+		serviceType: serviceType,
+		nodePort:    nodePort,
+	}
+}
+
+func (k *service) name() string {
+	if k.expose != nil {
+		return ExternalServiceName(k.common.name(), k.expose)
 	}
+	return k.common.name()
 }
 
 func (k *service) build() {
 	k.Service = &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   k.name(),
-			Labels: k.labels(),
+			Labels: k.podLabels(),
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: k.labels(),
-			Ports:    k.ports(),
+			Type:     k.serviceType,
+			Selector: k.podLabels(),
+			Ports:    k.ports(nil),
 		},
 	}
 }
-func (k *service) ports() []corev1.ServicePort {
-	ports := make([]corev1.ServicePort, 0, len(k.service.Expose))
-	for _, expose := range k.service.Expose {
-		ports = append(ports, corev1.ServicePort{
-			Name:       strconv.Itoa(int(expose.Port)),
-			Port:       exposeExternalPort(k.expose),
+
+// ports keeps the node ports assigned to the current ports
+func (k *service) ports(current []corev1.ServicePort) []corev1.ServicePort {
+	exposes := k.service.Expose
+	if k.expose != nil {
+		exposes = []*types.ManifestServiceExpose{k.expose}
+	}
+
+	ports := make([]corev1.ServicePort, 0, len(exposes))
+	for _, expose := range exposes {
+		port := corev1.ServicePort{
+			Name:       strings.ToLower(string(expose.Protocol())) + "-" + strconv.Itoa(int(expose.Port)),
+			Protocol:   expose.Protocol(),
+			Port:       exposeExternalPort(expose),
 			TargetPort: intstr.FromInt(int(expose.Port)),
-		})
+		}
+		if k.expose != nil {
+			port.NodePort = k.nodePort
+			for _, item := range current {
+				if port.NodePort == 0 && item.Port == port.Port && item.Protocol == port.Protocol {
+					port.NodePort = item.NodePort
+				}
+			}
+		}
+		ports = append(ports, port)
 	}
 	return ports
 }
@@ -70,9 +126,10 @@ func (k *service) Update(c *Client) (rollback func(c *Client) error, err error)
 	}
 
 	k.Service = obj.DeepCopy()
-	k.Service.Labels = k.labels()
-	k.Service.Spec.Selector = k.labels()
-	k.Service.Spec.Ports = k.ports()
+	k.Service.Labels = k.podLabels()
+	k.Service.Spec.Type = k.serviceType
+	k.Service.Spec.Selector = k.podLabels()
+	k.Service.Spec.Ports = k.ports(obj.Spec.Ports)
 
 	_, err = c.CoreV1().Services(k.ns()).Update(k.Service)
 	if err != nil {
@@ -0,0 +1,105 @@
+package kube
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	// kubeletTimeout bounds the summary of a node whose kubelet does not
+	// answer
+	kubeletTimeout = 5 * time.Second
+	// kubeletConcurrency bounds the summaries read at once
+	kubeletConcurrency = 16
+)
+
+// Network is the traffic of the default interface since it was created, in
+// bytes
+type Network struct {
+	RxBytes int64
+	TxBytes int64
+}
+
+// networkStats is the part of the kubelet stats/summary/v1alpha1 used
+type networkStats struct {
+	RxBytes *int64 `json:"rxBytes"`
+	TxBytes *int64 `json:"txBytes"`
+}
+
+func (s *networkStats) network() *Network {
+	network := &Network{}
+	if s == nil {
+		return network
+	}
+	if s.RxBytes != nil {
+		network.RxBytes = *s.RxBytes
+	}
+	if s.TxBytes != nil {
+		network.TxBytes = *s.TxBytes
+	}
+	return network
+}
+
+type summary struct {
+	Node struct {
+		Network *networkStats `json:"network"`
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Network *networkStats `json:"network"`
+	} `json:"pods"`
+}
+
+// kubeletSummary reads the summary of the node from its kubelet, through the
+// proxy of the api server, within the kubeletTimeout
+func kubeletSummary(c *Client, nodeName string) (*summary, error) {
+	data, err := c.CoreV1().RESTClient().Get().
+		Resource("nodes").Name(nodeName).SubResource("proxy").Suffix("stats/summary").
+		Timeout(kubeletTimeout).DoRaw()
+	if err != nil {
+		return nil, errors.Wrapf(err, "kubelet summary of %s", nodeName)
+	}
+
+	res := &summary{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, errors.Wrapf(err, "kubelet summary of %s", nodeName)
+	}
+	return res, nil
+}
+
+// kubeletSummaries reads the summaries of the nodes concurrently, the nodes
+// whose kubelet is not reachable are missing
+func kubeletSummaries(c *Client, nodeNames []string) map[string]*summary {
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, kubeletConcurrency)
+		res  = make(map[string]*summary, len(nodeNames))
+	)
+	for _, name := range nodeNames {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stats, err := kubeletSummary(c, name)
+			if err != nil {
+				glog.V(1).Infoln(err)
+				return
+			}
+			lock.Lock()
+			res[name] = stats
+			lock.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return res
+}
@@ -3,15 +3,20 @@ package kube
 import (
 	"os"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -23,6 +28,7 @@ import (
 var mockKube Kube
 
 // Kubernetes actions interface
+//
 //go:generate mockgen -package $GOPACKAGE -destination mock_kube.go github.com/Ankr-network/dccn-daemon/task/kube Kube
 type Kube interface {
 	Create(c *Client) (err error)
@@ -38,6 +44,7 @@ const manifestServiceLabelName = "ankr.network/manifest-service"
 type common struct {
 	namespace string
 	service   *types.ManifestService
+	options   *types.ServiceOptions
 }
 
 func (c *common) ns() string {
@@ -52,43 +59,227 @@ func (c *common) labels() map[string]string {
 	}
 }
 
-func (c *common) container() corev1.Container {
-	qcpu := resource.NewScaledQuantity(int64(c.service.Unit.CPU), resource.Milli)
-	qmem := resource.NewQuantity(int64(c.service.Unit.Memory), resource.DecimalSI)
-	qdisk := resource.NewQuantity(int64(c.service.Unit.Disk), resource.DecimalSI)
-
-	kcontainer := corev1.Container{
-		Name:  c.service.Name,
-		Image: c.service.Image,
-		Args:  c.service.Args,
+// podLabels selects the pods of the manifest service
+func (c *common) podLabels() map[string]string {
+	labels := c.labels()
+	labels[manifestServiceLabelName] = c.name()
+	return labels
+}
+
+// newContainer creates a container limited to the unit, with the requests
+// of the qos class
+func newContainer(name, image string, unit *types.ResourceUnit, resources *types.ResourceOptions) corev1.Container {
+	return corev1.Container{
+		Name:  name,
+		Image: image,
 		Resources: corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:              qcpu.DeepCopy(),
-				corev1.ResourceMemory:           qmem.DeepCopy(),
-				corev1.ResourceEphemeralStorage: qdisk.DeepCopy(),
-			},
+			Limits:   resourceList(unit),
+			Requests: resourceList(resources.Request(unit)),
 		},
 	}
+}
 
-	for _, env := range c.service.Env {
-		parts := strings.Split(env, "=")
-		switch len(parts) {
-		case 2:
-			kcontainer.Env = append(kcontainer.Env, corev1.EnvVar{Name: parts[0], Value: parts[1]})
-		case 1:
-			kcontainer.Env = append(kcontainer.Env, corev1.EnvVar{Name: parts[0]})
+func resourceList(unit *types.ResourceUnit) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:              *resource.NewScaledQuantity(int64(unit.CPU), resource.Milli),
+		corev1.ResourceMemory:           *resource.NewQuantity(int64(unit.Memory), resource.DecimalSI),
+		corev1.ResourceEphemeralStorage: *resource.NewQuantity(int64(unit.Disk), resource.DecimalSI),
+	}
+}
+
+// unit is the resource unit of the i-th container of the pod, the main
+// container is the 0th
+func (c *common) unit(i int) *types.ResourceUnit {
+	if pod := c.options.GetPod(); pod != nil && i < len(pod.Units) && pod.Units[i] != nil {
+		return pod.Units[i]
+	}
+	return c.service.Unit
+}
+
+// volumeMounts mounts the shared volumes of the pod
+func (c *common) volumeMounts() []corev1.VolumeMount {
+	pod := c.options.GetPod()
+	if pod == nil {
+		return nil
+	}
+
+	mounts := make([]corev1.VolumeMount, 0, len(pod.Volumes))
+	for name, path := range pod.Volumes {
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: path})
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Name < mounts[j].Name })
+	return mounts
+}
+
+func (c *common) securityContext() *corev1.SecurityContext {
+	return SecurityContext(c.options.GetSecurity())
+}
+
+// SecurityContext is the security context of the containers, without
+// privilege escalation unless relaxed. The images run as their user with the
+// runtime capabilities without security options, and as non-root without
+// capabilities unless relaxed with them.
+func SecurityContext(options *types.SecurityOptions) *corev1.SecurityContext {
+	privileged := false
+	if options == nil {
+		allowPrivilegeEscalation := false
+		return &corev1.SecurityContext{
+			Privileged:               &privileged,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
 		}
 	}
 
+	// the kubelet refuses a root user with RunAsNonRoot
+	runAsNonRoot := !options.RunsAsRoot()
+	allowPrivilegeEscalation := options.AllowPrivilegeEscalation
+	readOnlyRootFilesystem := options.ReadOnlyRootFilesystem
+	ctx := &corev1.SecurityContext{
+		RunAsNonRoot:             &runAsNonRoot,
+		RunAsUser:                options.RunAsUser,
+		Privileged:               &privileged,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+	for _, capability := range options.Capabilities {
+		ctx.Capabilities.Add = append(ctx.Capabilities.Add, corev1.Capability(capability))
+	}
+	return ctx
+}
+
+// seccompAnnotation sets the seccomp profile of the pod, the pinned api has
+// no security context field for it
+const seccompAnnotation = "seccomp.security.alpha.kubernetes.io/pod"
+
+// podAnnotations are the annotations of the pod template
+func (c *common) podAnnotations() map[string]string {
+	profile := types.SeccompRuntimeDefault
+	if options := c.options.GetSecurity(); options != nil && options.Seccomp != "" {
+		profile = options.Seccomp
+	}
+	return map[string]string{seccompAnnotation: profile}
+}
+
+func (c *common) container() corev1.Container {
+	kcontainer := newContainer(c.service.Name, c.service.Image, c.unit(0), c.options.GetResources())
+	kcontainer.Args = c.service.Args
+	kcontainer.VolumeMounts = c.volumeMounts()
+	kcontainer.SecurityContext = c.securityContext()
+
+	kcontainer.Env = containerEnv(c.service.Env)
+
 	for _, expose := range c.service.Expose {
 		kcontainer.Ports = append(kcontainer.Ports, corev1.ContainerPort{
 			ContainerPort: int32(expose.Port),
+			Protocol:      expose.Protocol(),
 		})
 	}
 
+	kcontainer.LivenessProbe = probe(c.options.GetLiveness())
+	kcontainer.ReadinessProbe = probe(c.options.GetReadiness())
+	if preStop := c.options.GetPreStop(); len(preStop) != 0 {
+		kcontainer.Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: preStop}},
+		}
+	}
+
 	return kcontainer
 }
 
+// containers returns the main container and the sidecars sharing the pod
+func (c *common) containers() []corev1.Container {
+	containers := []corev1.Container{c.container()}
+	for i, image := range c.options.GetPod().GetSidecars() {
+		sidecar := newContainer(c.service.Name+"-"+strconv.Itoa(i+1), image, c.unit(i+1), c.options.GetResources())
+		sidecar.Env = containerEnv(c.service.Env)
+		sidecar.VolumeMounts = c.volumeMounts()
+		sidecar.SecurityContext = c.securityContext()
+		containers = append(containers, sidecar)
+	}
+	return containers
+}
+
+// initContainers run in order to completion before the containers start
+func (c *common) initContainers() []corev1.Container {
+	var containers []corev1.Container
+	for i, image := range c.options.GetPod().GetInit() {
+		init := newContainer(c.service.Name+"-init-"+strconv.Itoa(i), image, c.service.Unit, c.options.GetResources())
+		init.Env = containerEnv(c.service.Env)
+		init.VolumeMounts = c.volumeMounts()
+		init.SecurityContext = c.securityContext()
+		containers = append(containers, init)
+	}
+	return containers
+}
+
+// volumes are the emptyDir volumes shared by the containers of the pod
+func (c *common) volumes() []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, mount := range c.volumeMounts() {
+		volumes = append(volumes, corev1.Volume{
+			Name:         mount.Name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+	return volumes
+}
+
+func (c *common) podSpec() corev1.PodSpec {
+	selector, affinity, tolerations := placement(c.options.GetRequirements())
+	return corev1.PodSpec{
+		InitContainers:                c.initContainers(),
+		Containers:                    c.containers(),
+		Volumes:                       c.volumes(),
+		TerminationGracePeriodSeconds: c.options.GetTerminationGracePeriod(),
+		NodeSelector:                  selector,
+		Affinity:                      affinity,
+		Tolerations:                   tolerations,
+	}
+}
+
+func containerEnv(envs []string) []corev1.EnvVar {
+	var res []corev1.EnvVar
+	for _, env := range envs {
+		parts := strings.Split(env, "=")
+		switch len(parts) {
+		case 2:
+			res = append(res, corev1.EnvVar{Name: parts[0], Value: parts[1]})
+		case 1:
+			res = append(res, corev1.EnvVar{Name: parts[0]})
+		}
+	}
+	return res
+}
+
+func probe(p *types.Probe) *corev1.Probe {
+	if p == nil {
+		return nil
+	}
+
+	kprobe := &corev1.Probe{
+		InitialDelaySeconds: p.InitialDelay,
+		PeriodSeconds:       p.Period,
+		TimeoutSeconds:      p.Timeout,
+		FailureThreshold:    p.FailureThreshold,
+	}
+	switch {
+	case p.HTTP != nil:
+		kprobe.HTTPGet = &corev1.HTTPGetAction{
+			Path: p.HTTP.Path,
+			Port: intstr.FromInt(int(p.HTTP.Port)),
+		}
+	case p.TCP != nil:
+		kprobe.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(p.TCP.Port)),
+		}
+	default:
+		kprobe.Exec = &corev1.ExecAction{Command: p.Exec}
+	}
+	return kprobe
+}
+
 func exposeExternalPort(expose *types.ManifestServiceExpose) int32 {
 	if expose.ExternalPort == 0 {
 		return int32(expose.Port)
@@ -99,6 +290,17 @@ func Selector() string {
 	req, _ := labels.NewRequirement(managedLabelName, selection.Equals, []string{"true"})
 	return labels.NewSelector().Add(*req).String()
 }
+
+// ServiceSelector selects the objects of a manifest service
+func ServiceSelector(name string) string {
+	req, _ := labels.NewRequirement(manifestServiceLabelName, selection.Equals, []string{name})
+	return labels.NewSelector().Add(*req).String()
+}
+
+// ManifestServiceName returns the manifest service owning the object
+func ManifestServiceName(obj metav1.Object) string {
+	return obj.GetLabels()[manifestServiceLabelName]
+}
 func IsNotFound(err error) bool {
 	if err != nil {
 		return strings.HasSuffix(err.Error(), "not found")
@@ -109,6 +311,10 @@ func IsNotFound(err error) bool {
 type Client struct {
 	kubernetes.Interface
 	metc metricsclient.Interface
+	dync dynamic.Interface
+
+	versions     *APIVersions
+	ingressClass string
 }
 
 func NewClient(cfgpath string) (*Client, error) {
@@ -127,7 +333,34 @@ func NewClient(cfgpath string) (*Client, error) {
 		return nil, errors.Wrap(err, "creating metrics client")
 	}
 
-	return &Client{kc, metc}, nil
+	dync, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating dynamic client")
+	}
+
+	versions, err := negotiateVersions(kc.Discovery())
+	if err != nil {
+		return nil, errors.Wrap(err, "negotiating api versions")
+	}
+	glog.V(1).Infof("api versions, ingress: %s, cronjob: %s, autoscaler: %s",
+		versions.Ingress, versions.CronJob, versions.Autoscaler)
+
+	return &Client{
+		Interface: kc,
+		metc:      metc,
+		dync:      dync,
+		versions:  versions,
+	}, nil
+}
+
+// Versions returns the api versions negotiated with the server
+func (c *Client) Versions() APIVersions {
+	return *c.versions
+}
+
+// SetIngressClass selects the ingress controller serving the task ingresses
+func (c *Client) SetIngressClass(class string) {
+	c.ingressClass = class
 }
 func openKubeConfig(cfgpath string) (*rest.Config, error) {
 	if cfgpath == "" {
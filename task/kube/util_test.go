@@ -0,0 +1,29 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSecurityContext(t *testing.T) {
+	// the root images start without security options
+	ctx := kube.SecurityContext(nil)
+	assert.Nil(t, ctx.RunAsNonRoot)
+	assert.Nil(t, ctx.Capabilities)
+	require.NotNil(t, ctx.AllowPrivilegeEscalation)
+	assert.False(t, *ctx.AllowPrivilegeEscalation)
+
+	ctx = kube.SecurityContext(&types.SecurityOptions{})
+	require.NotNil(t, ctx.RunAsNonRoot)
+	assert.True(t, *ctx.RunAsNonRoot)
+	assert.Equal(t, []corev1.Capability{"ALL"}, ctx.Capabilities.Drop)
+
+	ctx = kube.SecurityContext(&types.SecurityOptions{RunAsRoot: true, Capabilities: []string{"NET_BIND_SERVICE"}})
+	assert.False(t, *ctx.RunAsNonRoot)
+	assert.Equal(t, []corev1.Capability{"NET_BIND_SERVICE"}, ctx.Capabilities.Add)
+}
@@ -0,0 +1,136 @@
+package kube
+
+import (
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// API versions of the resources whose served version depends on the server.
+// The newer ones are not in the pinned clientset, they are accessed with the
+// dynamic client.
+const (
+	IngressExtensionsV1beta1 = "extensions/v1beta1"
+	IngressNetworkingV1      = "networking.k8s.io/v1"
+
+	CronJobBatchV1beta1 = "batch/v1beta1"
+	CronJobBatchV1      = "batch/v1"
+
+	AutoscalerV2beta1 = "autoscaling/v2beta1"
+	AutoscalerV2      = "autoscaling/v2"
+)
+
+var (
+	ingressV1Resource    = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	cronJobV1Resource    = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+	autoscalerV2Resource = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+)
+
+// APIVersions is the versions negotiated with the server at startup
+type APIVersions struct {
+	Ingress    string
+	CronJob    string
+	Autoscaler string
+}
+
+// negotiateVersions prefers the newest version the server offers
+func negotiateVersions(d discovery.DiscoveryInterface) (*APIVersions, error) {
+	versions := &APIVersions{
+		Ingress:    IngressExtensionsV1beta1,
+		CronJob:    CronJobBatchV1beta1,
+		Autoscaler: AutoscalerV2beta1,
+	}
+
+	ok, err := serves(d, IngressNetworkingV1, "ingresses")
+	if err != nil {
+		return nil, errors.Wrap(err, "discover ingress")
+	}
+	if ok {
+		versions.Ingress = IngressNetworkingV1
+	}
+
+	ok, err = serves(d, CronJobBatchV1, "cronjobs")
+	if err != nil {
+		return nil, errors.Wrap(err, "discover cronjob")
+	}
+	if ok {
+		versions.CronJob = CronJobBatchV1
+	}
+
+	ok, err = serves(d, AutoscalerV2, "horizontalpodautoscalers")
+	if err != nil {
+		return nil, errors.Wrap(err, "discover autoscaler")
+	}
+	if ok {
+		versions.Autoscaler = AutoscalerV2
+	}
+
+	return versions, nil
+}
+
+func serves(d discovery.DiscoveryInterface, groupVersion, resource string) (bool, error) {
+	list, err := d.ServerResourcesForGroupVersion(groupVersion)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, item := range list.APIResources {
+		if item.Name == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// toUnstructured converts the typed object into the given api version, the
+// schema of both versions must be the same
+func toUnstructured(obj interface{}, apiVersion, kind string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "to unstructured")
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	return u, nil
+}
+
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj)
+	return errors.Wrap(err, "from unstructured")
+}
+
+// updateUnstructured replaces the spec and labels of the current object, or
+// only the fields of the spec if any, the others are kept
+func updateUnstructured(current, desired *unstructured.Unstructured, fields ...string) (*unstructured.Unstructured, error) {
+	spec, _, err := unstructured.NestedMap(desired.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+
+	obj := current.DeepCopy()
+	obj.SetLabels(desired.GetLabels())
+	if len(fields) == 0 {
+		if err := unstructured.SetNestedField(obj.Object, spec, "spec"); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+	for _, field := range fields {
+		value, ok := spec[field]
+		if !ok {
+			unstructured.RemoveNestedField(obj.Object, "spec", field)
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, value, "spec", field); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,138 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+)
+
+// NodeMetrics is the capacity and the usage of a node, CPU in milli cores and
+// the others in bytes
+type NodeMetrics struct {
+	Allocatable kube.Metric
+	Used        kube.Metric
+	// Requested by the pods scheduled on the node, of all namespaces
+	Requested kube.Metric
+	// Allocatable less requested, zero if the node is cordoned or not ready
+	Schedulable   kube.Metric
+	Unschedulable bool `json:",omitempty"`
+
+	Pods        int64
+	PodCapacity int64
+	Images      []string `json:",omitempty"`
+	Network     kube.Network
+	// Provider attributes of the node labels
+	Attributes []types.ProviderAttribute `json:",omitempty"`
+}
+
+// TaskMetrics is the usage of the running pods of a task
+type TaskMetrics struct {
+	Replicas  int32
+	Pods      int64
+	Requested kube.Metric
+	Consumed  kube.Metric
+	Network   kube.Network
+
+	// Requested by the pods on each node
+	NodeRequested map[string]*kube.Metric `json:",omitempty"`
+}
+
+// breakdown fills the metrics of the nodes and the tasks, and the network
+// totals
+func (m *Metrics) breakdown(result *kube.Metrics, pods []kube.PodUsage) {
+	m.Nodes = make(map[string]*NodeMetrics, len(result.NodeTotal))
+	for name, total := range result.NodeTotal {
+		node := &NodeMetrics{
+			Allocatable:   *total,
+			Unschedulable: !result.NodeSchedulable[name],
+			Pods:          result.NodePods[name],
+			PodCapacity:   result.NodePodCapacity[name],
+			Attributes:    result.NodeAttributes[name],
+		}
+		if used, ok := result.NodeInUse[name]; ok {
+			node.Used = *used
+		}
+		if requested, ok := result.NodeRequested[name]; ok {
+			node.Requested = *requested
+		}
+		if !node.Unschedulable {
+			node.Schedulable = kube.Metric{
+				CPU:              remaining(total.CPU, node.Requested.CPU),
+				Memory:           remaining(total.Memory, node.Requested.Memory),
+				Storage:          remaining(total.Storage, node.Requested.Storage),
+				EphemeralStorage: remaining(total.EphemeralStorage, node.Requested.EphemeralStorage),
+			}
+		}
+		for _, names := range result.NodeImages[name] {
+			if image := imageName(names); image != "" {
+				node.Images = append(node.Images, image)
+			}
+		}
+		if network, ok := result.NodeNetwork[name]; ok {
+			node.Network = *network
+			m.NetworkIO += network.RxBytes + network.TxBytes
+		}
+
+		m.Pods += node.Pods
+		m.PodCapacity += node.PodCapacity
+		m.Nodes[name] = node
+	}
+
+	m.Tasks = map[string]*TaskMetrics{}
+	for name, replicas := range m.Replicas {
+		m.Tasks[name] = &TaskMetrics{Replicas: replicas}
+	}
+	for _, pod := range pods {
+		if !pod.FinishTime.IsZero() || pod.Service == "" {
+			continue
+		}
+		task, ok := m.Tasks[pod.Service]
+		if !ok {
+			task = &TaskMetrics{}
+			m.Tasks[pod.Service] = task
+		}
+
+		task.Pods++
+		addMetric(&task.Requested, &pod.Requested)
+		if task.NodeRequested == nil {
+			task.NodeRequested = map[string]*kube.Metric{}
+		}
+		if _, ok := task.NodeRequested[pod.Node]; !ok {
+			task.NodeRequested[pod.Node] = &kube.Metric{}
+		}
+		addMetric(task.NodeRequested[pod.Node], &pod.Requested)
+		addMetric(&task.Consumed, &pod.Consumed)
+		if network, ok := result.PodNetwork[pod.Namespace+"/"+pod.Name]; ok {
+			task.Network.RxBytes += network.RxBytes
+			task.Network.TxBytes += network.TxBytes
+		}
+	}
+}
+
+func remaining(total, requested int64) int64 {
+	if requested > total {
+		return 0
+	}
+	return total - requested
+}
+
+func addMetric(m, o *kube.Metric) {
+	m.CPU += o.CPU
+	m.Memory += o.Memory
+	m.Storage += o.Storage
+	m.EphemeralStorage += o.EphemeralStorage
+}
+
+// imageName prefers the tagged name of the image to the digests
+func imageName(names []string) string {
+	for _, name := range names {
+		if !strings.Contains(name, "@") {
+			return name
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+)
+
+// SecurityPolicy is the relaxations of the hardened container defaults the
+// tenants of the datacenter may request, the zero policy allows none
+type SecurityPolicy struct {
+	AllowRoot                bool     `json:"allowRoot,omitempty"`
+	AllowPrivilegeEscalation bool     `json:"allowPrivilegeEscalation,omitempty"`
+	AllowedCapabilities      []string `json:"allowedCapabilities,omitempty"`
+	// Seccomp profiles allowed besides runtime/default
+	AllowedSeccomp []string `json:"allowedSeccomp,omitempty"`
+}
+
+// Check rejects the security options the policy does not allow
+func (p *SecurityPolicy) Check(options *types.SecurityOptions) error {
+	if options == nil {
+		return nil
+	}
+
+	if options.RunsAsRoot() && !p.AllowRoot {
+		return errors.New("security policy: running as root not allowed")
+	}
+	if options.AllowPrivilegeEscalation && !p.AllowPrivilegeEscalation {
+		return errors.New("security policy: privilege escalation not allowed")
+	}
+	for _, capability := range options.Capabilities {
+		if !contains(p.AllowedCapabilities, capability) {
+			return errors.Errorf("security policy: capability %s not allowed", capability)
+		}
+	}
+	if options.Seccomp != "" && options.Seccomp != types.SeccompRuntimeDefault &&
+		!contains(p.AllowedSeccomp, options.Seccomp) {
+		return errors.Errorf("security policy: seccomp profile %s not allowed", options.Seccomp)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSecurityPolicy sets the relaxations the tasks may request, the tasks
+// violating it are rejected before any object is created
+func (t *Tasker) SetSecurityPolicy(policy *SecurityPolicy) {
+	t.policy = policy
+}
+
+// SetRunAsNonRoot hardens the tasks without security options as if their
+// options were empty, the containers run as non-root without capabilities
+// and the root images do not start
+func (t *Tasker) SetRunAsNonRoot(nonRoot bool) {
+	t.nonRoot = nonRoot
+}
+
+// secure sets the empty security options of the tasks hardened by default
+func (t *Tasker) secure(options *types.ServiceOptions) *types.ServiceOptions {
+	if !t.nonRoot || options.GetSecurity() != nil {
+		return options
+	}
+
+	res := &types.ServiceOptions{}
+	if options != nil {
+		*res = *options
+	}
+	res.Security = &types.SecurityOptions{}
+	return res
+}
+
+// validate checks the options of the service and the datacenter policies
+func (t *Tasker) validate(service *types.ManifestService, options *types.ServiceOptions, images ...string) error {
+	if err := options.Validate(service); err != nil {
+		return err
+	}
+	if err := t.policy.Check(options.GetSecurity()); err != nil {
+		return err
+	}
+	return t.checkImages(options, images...)
+}
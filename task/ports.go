@@ -0,0 +1,90 @@
+package task
+
+import (
+	"sync"
+
+	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodePorts allocates the node ports of global TCP/UDP exposes. Ports in use
+// are read from the services of all namespaces on every allocation, the
+// reserved ones cover the ports allocated but not created yet.
+type nodePorts struct {
+	sync.Mutex
+	min, max int32
+	reserved map[int32]bool
+}
+
+func newNodePorts(min, max int32) *nodePorts {
+	return &nodePorts{
+		min:      min,
+		max:      max,
+		reserved: map[int32]bool{},
+	}
+}
+
+// allocate returns the node port of each expose, exposes already served by
+// a service keep their port
+func (p *nodePorts) allocate(c *kube.Client, namespace string, service *types.ManifestService,
+	exposes []*types.ManifestServiceExpose) (map[*types.ManifestServiceExpose]int32, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	list := &corev1.ServiceList{}
+	if err := kube.NewService("", service).List(c, list); err != nil {
+		return nil, errors.Wrap(err, "allocate node port")
+	}
+
+	used := map[int32]bool{}
+	current := map[string]map[corev1.Protocol]int32{}
+	for _, item := range list.Items {
+		for _, port := range item.Spec.Ports {
+			if port.NodePort == 0 {
+				continue
+			}
+			used[port.NodePort] = true
+			if item.Namespace == namespace {
+				if current[item.Name] == nil {
+					current[item.Name] = map[corev1.Protocol]int32{}
+				}
+				current[item.Name][port.Protocol] = port.NodePort
+			}
+		}
+	}
+
+	res := map[*types.ManifestServiceExpose]int32{}
+	next := p.min
+	for _, expose := range exposes {
+		if port, ok := current[kube.ExternalServiceName(service.Name, expose)][expose.Protocol()]; ok {
+			res[expose] = port
+			continue
+		}
+
+		for next <= p.max && (used[next] || p.reserved[next]) {
+			next++
+		}
+		if next > p.max {
+			for _, port := range res {
+				delete(p.reserved, port)
+			}
+			return nil, errors.Errorf("allocate node port: no free port in %d-%d", p.min, p.max)
+		}
+		p.reserved[next] = true
+		res[expose] = next
+		next++
+	}
+	return res, nil
+}
+
+// release drops the reservations once the services are created or failed
+func (p *nodePorts) release(ports map[*types.ManifestServiceExpose]int32) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, port := range ports {
+		delete(p.reserved, port)
+	}
+}
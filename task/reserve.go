@@ -0,0 +1,75 @@
+package task
+
+import (
+	"github.com/Ankr-network/dccn-daemon/types"
+)
+
+// Reserver reserves the resources of the pods of a task before it is
+// created or updated, of its order if any, implemented by the
+// inventory.Inventory
+type Reserver interface {
+	Reserve(name, order string, unit types.ResourceUnit, pods uint32) error
+	Release(name string)
+}
+
+// SetReserver sets the reserver refusing the tasks which do not fit, the
+// tasks are not checked if nil
+func (t *Tasker) SetReserver(reserver Reserver) {
+	t.reserver = reserver
+}
+
+// reserve reserves the requests of the replicas of the deployments of the
+// service, the autoscaled ones start with the min replicas
+func (t *Tasker) reserve(name string, service *types.ManifestService, options *types.ServiceOptions,
+	deployments int) error {
+	if t.reserver == nil {
+		return nil
+	}
+
+	replicas := service.Count
+	if autoscale := options.GetAutoscale(); autoscale != nil {
+		replicas = autoscale.Min
+	}
+	return t.reserver.Reserve(name, options.GetOrderID(), PodRequest(service, options), replicas*uint32(deployments))
+}
+
+func (t *Tasker) release(name string) {
+	if t.reserver != nil {
+		t.reserver.Release(name)
+	}
+}
+
+// PodUnit is the resources of a pod of the service, the sum of the units of
+// its containers
+func PodUnit(service *types.ManifestService, options *types.ServiceOptions) types.ResourceUnit {
+	return podSum(service, options, func(unit *types.ResourceUnit) *types.ResourceUnit { return unit })
+}
+
+// PodRequest is the requests of a pod of the service, the units of its
+// containers for the guaranteed pods
+func PodRequest(service *types.ManifestService, options *types.ServiceOptions) types.ResourceUnit {
+	return podSum(service, options, options.GetResources().Request)
+}
+
+func podSum(service *types.ManifestService, options *types.ServiceOptions,
+	containerUnit func(unit *types.ResourceUnit) *types.ResourceUnit) types.ResourceUnit {
+	var units []*types.ResourceUnit
+	if pod := options.GetPod(); pod != nil {
+		units = pod.Units
+	}
+
+	pod := types.ResourceUnit{}
+	for i := 0; i <= len(options.GetPod().GetSidecars()); i++ {
+		unit := service.Unit
+		if i < len(units) && units[i] != nil {
+			unit = units[i]
+		}
+		if unit != nil {
+			unit = containerUnit(unit)
+			pod.CPU += unit.CPU
+			pod.Memory += unit.Memory
+			pod.Disk += unit.Disk
+		}
+	}
+	return pod
+}
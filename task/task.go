@@ -2,16 +2,27 @@ package task
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Ankr-network/dccn-daemon/task/kube"
 	"github.com/Ankr-network/dccn-daemon/types"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/api/extensions/v1beta1"
 )
 
-func (t *Tasker) CreateTasks(name string, images ...string) error {
+func (t *Tasker) CreateTasks(name string, options *types.ServiceOptions, images ...string) error {
+	options = t.secure(options)
+	images, options = t.podImages(images, options)
+	if err := t.validate(types.NewManifestService(name, ""), options, images...); err != nil {
+		return err
+	}
+
 	kubes := []kube.Kube{kube.NewPrepare(t.ns, &types.ManifestService{Name: name})}
 
 	count := len(images)
@@ -20,19 +31,31 @@ func (t *Tasker) CreateTasks(name string, images ...string) error {
 		return errors.New("no image")
 	case 1:
 		service := types.NewManifestService(name, images[0])
-		kubes = append(kubes, kube.NewDeployment(t.ns, service))
+		kubes = append(kubes, t.deployment(service, options)...)
 	default:
 		for i := range images {
 			nameI := name + "-" + strconv.Itoa(i)
 			service := types.NewManifestService(nameI, images[i])
-			kubes = append(kubes, kube.NewDeployment(t.ns, service))
+			kubes = append(kubes, t.deployment(service, options)...)
 		}
 	}
 
-	return t.updateOrCreate(kubes)
+	if err := t.reserve(name, types.NewManifestService(name, ""), options, count); err != nil {
+		return err
+	}
+	if err := t.updateOrCreate(kubes); err != nil {
+		t.release(name)
+		return err
+	}
+	return nil
 }
 
-func (t *Tasker) CreateJobs(name, crontab string, images ...string) error {
+func (t *Tasker) CreateJobs(name, crontab string, options *types.ServiceOptions, images ...string) error {
+	options = t.secure(options)
+	if err := t.validate(types.NewJobManifestService(name, ""), options, images...); err != nil {
+		return err
+	}
+
 	kubes := []kube.Kube{kube.NewPrepare(t.ns, &types.ManifestService{Name: name})}
 
 	count := len(images)
@@ -42,45 +65,249 @@ func (t *Tasker) CreateJobs(name, crontab string, images ...string) error {
 	case 1:
 		service := types.NewManifestService(name, images[0])
 		if crontab == "" {
-			kubes = append(kubes, kube.NewJob(t.ns, service))
+			kubes = append(kubes, kube.NewJob(t.ns, service, options))
 		} else {
-			kubes = append(kubes, kube.NewCronJob(t.ns, service, crontab))
+			kubes = append(kubes, kube.NewCronJob(t.ns, service, options, crontab))
 		}
 	default:
 		for i := range images {
 			nameI := name + "-" + strconv.Itoa(i)
 			service := types.NewManifestService(nameI, images[i])
 			if crontab == "" {
-				kubes = append(kubes, kube.NewJob(t.ns, service))
+				kubes = append(kubes, kube.NewJob(t.ns, service, options))
 			} else {
-				kubes = append(kubes, kube.NewCronJob(t.ns, service, crontab))
+				kubes = append(kubes, kube.NewCronJob(t.ns, service, options, crontab))
 			}
 		}
 	}
 
-	return t.updateOrCreate(kubes)
+	if err := t.reserve(name, types.NewJobManifestService(name, ""), options, count); err != nil {
+		return err
+	}
+	if err := t.updateOrCreate(kubes); err != nil {
+		t.release(name)
+		return err
+	}
+	return nil
 }
 
-func (t *Tasker) UpdateTask(name, image string, replicas, internalPort, externalPort uint32) error {
-	kubes := []kube.Kube{kube.NewPrepare(t.ns, &types.ManifestService{Name: name})}
+// UpdateTask updates the task to the images, the containers of a pod task or
+// the deployments of the task otherwise
+func (t *Tasker) UpdateTask(name string, images []string, replicas, internalPort, externalPort uint32,
+	options *types.ServiceOptions) error {
+	options = t.secure(options)
+	images, options = t.podImages(images, options)
+	switch len(images) {
+	case 0:
+		return errors.New("no image")
+	case 1:
+	default:
+		return t.updateDeployments(name, images, replicas, options)
+	}
+	image := images[0]
 
+	kubes := []kube.Kube{kube.NewPrepare(t.ns, &types.ManifestService{Name: name})}
 	service := types.NewManifestService(name, image)
 	service.Count = replicas
-	if internalPort != 0 && externalPort != 0 {
+	if err := t.validate(service, options, image); err != nil {
+		return err
+	}
+	if len(options.GetExpose()) != 0 {
+		service.Expose = options.GetExpose()
+	} else if internalPort != 0 && externalPort != 0 {
 		service.Expose = []*types.ManifestServiceExpose{&types.ManifestServiceExpose{
 			Port:         internalPort,
 			ExternalPort: externalPort,
-			Proto:        string(corev1.ProtocolTCP),
-			Service:      name,
+			Proto:        types.ExposeHTTP,
 			Global:       true,
-			Hosts:        []string{},
 		}}
 	}
 
-	kubes = append(kubes, kube.NewDeployment(t.ns, service))
-	kubes = append(kubes, kube.NewService(t.ns, service, service.Expose[0]))
-	kubes = append(kubes, kube.NewIngress(t.ns, service, service.Expose[0]))
-	return t.updateOrCreate(kubes)
+	// non-global exposes are served by the cluster ip service only
+	var ingress *types.ManifestServiceExpose
+	var externals []*types.ManifestServiceExpose
+	for _, expose := range service.Expose {
+		expose.Service = name
+		switch {
+		case !expose.Global:
+		case expose.IsHTTP():
+			if ingress == nil {
+				ingress = expose
+			}
+		default:
+			externals = append(externals, expose)
+		}
+	}
+
+	if ingress != nil {
+		ingress.Hosts = append([]string{t.taskHost(name)}, options.GetDomains()...)
+
+		secret, err := t.tlsSecret(service, ingress.Hosts, options)
+		if err != nil {
+			return err
+		}
+		if secret != nil {
+			kubes = append(kubes, secret)
+		}
+	}
+
+	kubes = append(kubes, t.deployment(service, options)...)
+	kubes = append(kubes, kube.NewService(t.ns, service))
+	if ingress != nil {
+		kubes = append(kubes, kube.NewIngress(t.ns, service, ingress, options))
+	}
+
+	nodePorts := map[*types.ManifestServiceExpose]int32{}
+	if len(externals) != 0 && t.serviceType == corev1.ServiceTypeNodePort {
+		var err error
+		if nodePorts, err = t.nodePorts.allocate(t.client, t.ns, service, externals); err != nil {
+			return err
+		}
+		defer t.nodePorts.release(nodePorts)
+	}
+	for _, expose := range externals {
+		kubes = append(kubes, kube.NewExternalService(t.ns, service, expose, t.serviceType, nodePorts[expose]))
+	}
+
+	if err := t.reserve(name, service, options, 1); err != nil {
+		return err
+	}
+	if err := t.updateOrCreate(kubes); err != nil {
+		t.release(name)
+		return err
+	}
+	return t.prune(service, ingress != nil, options.GetAutoscale() != nil, externals)
+}
+
+// updateDeployments updates the deployments of a task of several images,
+// which have no service as created by CreateTasks
+func (t *Tasker) updateDeployments(name string, images []string, replicas uint32,
+	options *types.ServiceOptions) error {
+	if err := t.validate(types.NewManifestService(name, ""), options, images...); err != nil {
+		return err
+	}
+
+	kubes := []kube.Kube{kube.NewPrepare(t.ns, &types.ManifestService{Name: name})}
+	for i := range images {
+		service := types.NewManifestService(name+"-"+strconv.Itoa(i), images[i])
+		service.Count = replicas
+		kubes = append(kubes, t.deployment(service, options)...)
+	}
+
+	service := types.NewManifestService(name, "")
+	service.Count = replicas
+	if err := t.reserve(name, service, options, len(images)); err != nil {
+		return err
+	}
+	if err := t.updateOrCreate(kubes); err != nil {
+		t.release(name)
+		return err
+	}
+	return nil
+}
+
+// deployment returns the deployment of the service and its autoscaler
+func (t *Tasker) deployment(service *types.ManifestService, options *types.ServiceOptions) []kube.Kube {
+	kubes := []kube.Kube{kube.NewDeployment(t.ns, service, options)}
+	if options.GetAutoscale() != nil {
+		kubes = append(kubes, kube.NewAutoscaler(t.ns, service, options))
+	}
+	return kubes
+}
+
+// prune deletes the ingress, autoscaler and external services no longer
+// requested
+func (t *Tasker) prune(service *types.ManifestService, ingress, autoscale bool,
+	externals []*types.ManifestServiceExpose) error {
+	if !ingress {
+		err := kube.NewIngress(t.ns, service, nil, nil).Delete(t.client)
+		if err != nil && !kube.IsNotFound(err) {
+			return err
+		}
+	}
+	if !autoscale {
+		err := kube.NewAutoscaler(t.ns, service, nil).Delete(t.client)
+		if err != nil && !kube.IsNotFound(err) {
+			return err
+		}
+	}
+
+	keep := map[string]bool{service.Name: true}
+	for _, expose := range externals {
+		keep[kube.ExternalServiceName(service.Name, expose)] = true
+	}
+
+	list := &corev1.ServiceList{}
+	if err := kube.NewService(t.ns, service).List(t.client, list); err != nil {
+		return err
+	}
+	for _, item := range list.Items {
+		if kube.ManifestServiceName(&item) != service.Name || keep[item.Name] {
+			continue
+		}
+		if err := kube.NewService(t.ns, &types.ManifestService{Name: item.Name}).Delete(t.client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Endpoints lists the public endpoints of the task, the node ports are
+// reachable on the ingress host
+func (t *Tasker) Endpoints(name string) ([]string, error) {
+	endpoints := []string{}
+
+	ingresses := &extv1.IngressList{}
+	if err := kube.NewIngress(t.ns, &types.ManifestService{}, nil, nil).List(t.client, ingresses); err != nil {
+		return nil, err
+	}
+	for _, item := range ingresses.Items {
+		if item.Name != name {
+			continue
+		}
+
+		tls := map[string]bool{}
+		for _, entry := range item.Spec.TLS {
+			for _, host := range entry.Hosts {
+				tls[host] = true
+			}
+		}
+		for _, rule := range item.Spec.Rules {
+			if tls[rule.Host] {
+				endpoints = append(endpoints, "https://"+rule.Host)
+			} else {
+				endpoints = append(endpoints, "http://"+rule.Host)
+			}
+		}
+	}
+
+	services := &corev1.ServiceList{}
+	if err := kube.NewService(t.ns, &types.ManifestService{}).List(t.client, services); err != nil {
+		return nil, err
+	}
+	for _, item := range services.Items {
+		if kube.ManifestServiceName(&item) != name {
+			continue
+		}
+
+		for _, port := range item.Spec.Ports {
+			proto := strings.ToLower(string(port.Protocol))
+			switch item.Spec.Type {
+			case corev1.ServiceTypeNodePort:
+				endpoints = append(endpoints, fmt.Sprintf("%s://%s:%d", proto, t.host, port.NodePort))
+			case corev1.ServiceTypeLoadBalancer:
+				for _, ingress := range item.Status.LoadBalancer.Ingress {
+					host := ingress.IP
+					if host == "" {
+						host = ingress.Hostname
+					}
+					endpoints = append(endpoints, fmt.Sprintf("%s://%s:%d", proto, host, port.Port))
+				}
+			}
+		}
+	}
+
+	return endpoints, nil
 }
 
 func (t *Tasker) CancelTask(name string) error {
@@ -88,15 +315,25 @@ func (t *Tasker) CancelTask(name string) error {
 	service.Count = 0
 	expose := &types.ManifestServiceExpose{}
 
-	if err := kube.NewDeployment(t.ns, service).Delete(t.client); err != nil {
+	if err := kube.NewDeployment(t.ns, service, nil).Delete(t.client); err != nil {
+		return err
+	}
+	if err := kube.NewService(t.ns, service).Delete(t.client); err != nil {
+		return err
+	}
+	if err := kube.NewIngress(t.ns, service, expose, nil).Delete(t.client); err != nil {
+		return err
+	}
+	if err := t.prune(service, true, false, nil); err != nil {
 		return err
 	}
-	if err := kube.NewService(t.ns, service, expose).Delete(t.client); err != nil {
+	if err := t.AbortCanary(name); err != nil {
 		return err
 	}
-	if err := kube.NewIngress(t.ns, service, expose).Delete(t.client); err != nil {
+	if err := kube.NewTLSSecret(t.ns, service, nil, nil).Delete(t.client); err != nil && !kube.IsNotFound(err) {
 		return err
 	}
+	t.release(name)
 	return nil
 }
 func (t *Tasker) CancelJob(name, crontab string) error {
@@ -105,27 +342,28 @@ func (t *Tasker) CancelJob(name, crontab string) error {
 	expose := &types.ManifestServiceExpose{}
 
 	if crontab == "" {
-		if err := kube.NewJob(t.ns, service).Delete(t.client); err != nil {
+		if err := kube.NewJob(t.ns, service, nil).Delete(t.client); err != nil {
 			return err
 		}
 	} else {
-		if err := kube.NewCronJob(t.ns, service, crontab).Delete(t.client); err != nil {
+		if err := kube.NewCronJob(t.ns, service, nil, crontab).Delete(t.client); err != nil {
 			return err
 		}
 	}
 
-	if err := kube.NewService(t.ns, service, expose).Delete(t.client); err != nil {
+	if err := kube.NewService(t.ns, service).Delete(t.client); err != nil {
 		return err
 	}
-	if err := kube.NewIngress(t.ns, service, expose).Delete(t.client); err != nil {
+	if err := kube.NewIngress(t.ns, service, expose, nil).Delete(t.client); err != nil {
 		return err
 	}
+	t.release(name)
 	return nil
 }
 
 func (t *Tasker) ListTask() ([]string, error) {
 	res := &appsv1.DeploymentList{}
-	if err := kube.NewDeployment(t.ns, &types.ManifestService{}).List(t.client, res); err != nil {
+	if err := kube.NewDeployment(t.ns, &types.ManifestService{}, nil).List(t.client, res); err != nil {
 		return nil, err
 	}
 	if len(res.Items) == 0 {
@@ -140,8 +378,92 @@ func (t *Tasker) ListTask() ([]string, error) {
 	return contents, nil
 }
 
-func (t *Tasker) Metering() (map[string]*types.ResourceUnit, error) {
-	result := &map[string]*types.ResourceUnit{}
+// ServiceStatus returns the rollout status of the deployment
+func (t *Tasker) ServiceStatus(name string) (*types.ServiceStatusResponse, error) {
+	res := &appsv1.DeploymentList{}
+	if err := kube.NewDeployment(t.ns, &types.ManifestService{}, nil).List(t.client, res); err != nil {
+		return nil, err
+	}
+
+	for _, item := range res.Items {
+		if item.Name != name {
+			continue
+		}
+
+		status := &types.ServiceStatusResponse{
+			ObservedGeneration: item.Status.ObservedGeneration,
+			Replicas:           item.Status.Replicas,
+			UpdatedReplicas:    item.Status.UpdatedReplicas,
+			ReadyReplicas:      item.Status.ReadyReplicas,
+			AvailableReplicas:  item.Status.AvailableReplicas,
+		}
+		// the rollout is not observed yet
+		if item.Status.ObservedGeneration < item.Generation {
+			status.UpdatedReplicas = 0
+		}
+		if item.Spec.Replicas != nil {
+			status.Replicas = *item.Spec.Replicas
+		}
+		return status, nil
+	}
+	return nil, errors.Errorf("deployment %s not found", name)
+}
+
+// WaitReady waits the rollout of the deployments of the task, one per image
+// in the multiple deployments mode, no wait if the ready timeout is 0
+func (t *Tasker) WaitReady(name string) error {
+	if t.readyTimeout <= 0 {
+		return nil
+	}
+
+	res := &appsv1.DeploymentList{}
+	if err := kube.NewDeployment(t.ns, &types.ManifestService{}, nil).List(t.client, res); err != nil {
+		return err
+	}
+	names := []string{}
+	for _, item := range res.Items {
+		if types.IsTaskService(name, item.Name) {
+			names = append(names, item.Name)
+		}
+	}
+	if len(names) == 0 {
+		return errors.Errorf("deployment %s not found", name)
+	}
+	sort.Strings(names)
+	return t.waitReady(names...)
+}
+
+// waitReady waits the rollout of the deployments until all the replicas are
+// updated and pass the readiness checks, no wait if the timeout is 0
+func (t *Tasker) waitReady(names ...string) error {
+	if t.readyTimeout <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(t.readyTimeout)
+	for _, name := range names {
+		for {
+			status, err := t.ServiceStatus(name)
+			if err != nil {
+				return err
+			}
+			if status.UpdatedReplicas == status.Replicas && status.ReadyReplicas >= status.Replicas {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				return errors.Errorf("%s not ready in %s, ready %d/%d", name, t.readyTimeout,
+					status.ReadyReplicas, status.Replicas)
+			}
+			time.Sleep(2 * time.Second)
+		}
+	}
+	return nil
+}
+
+// PodUsage samples the requested and consumed resources of the running pods
+func (t *Tasker) PodUsage() ([]kube.PodUsage, error) {
+	result := &[]kube.PodUsage{}
 	if err := kube.NewMetering(t.ns, &types.ManifestService{}).List(t.client, result); err != nil {
 		return nil, err
 	}
@@ -158,7 +480,18 @@ type Metrics struct {
 
 	ImageCount    int64
 	EndPointCount int64
-	NetworkIO     int64 // No data
+	// Bytes received and sent by the nodes, of the kubelets reachable
+	NetworkIO   int64
+	Pods        int64
+	PodCapacity int64
+
+	// Replicas of each deployment, changed by the autoscalers
+	Replicas map[string]int32 `json:",omitempty"`
+	// Provider attributes of the nodes, matched with the order requirements
+	Attributes []types.ProviderAttribute `json:",omitempty"`
+
+	Nodes map[string]*NodeMetrics `json:",omitempty"`
+	Tasks map[string]*TaskMetrics `json:",omitempty"`
 }
 
 func (t *Tasker) Metrics() (*Metrics, error) {
@@ -185,5 +518,36 @@ func (t *Tasker) Metrics() (*Metrics, error) {
 		metrics.EndPointCount += count
 	}
 
+	advertised := map[string]bool{}
+	for _, attrs := range result.NodeAttributes {
+		for _, attr := range attrs {
+			if key := attr.Name + "=" + attr.Value; !advertised[key] {
+				advertised[key] = true
+				metrics.Attributes = append(metrics.Attributes, attr)
+			}
+		}
+	}
+	sort.Slice(metrics.Attributes, func(i, j int) bool {
+		if metrics.Attributes[i].Name != metrics.Attributes[j].Name {
+			return metrics.Attributes[i].Name < metrics.Attributes[j].Name
+		}
+		return metrics.Attributes[i].Value < metrics.Attributes[j].Value
+	})
+
+	deployments := &appsv1.DeploymentList{}
+	if err := kube.NewDeployment(t.ns, &types.ManifestService{}, nil).List(t.client, deployments); err != nil {
+		return nil, err
+	}
+	metrics.Replicas = make(map[string]int32, len(deployments.Items))
+	for _, item := range deployments.Items {
+		metrics.Replicas[item.Name] = item.Status.Replicas
+	}
+
+	pods, err := t.PodUsage()
+	if err != nil {
+		return nil, err
+	}
+	metrics.breakdown(result, pods)
+
 	return metrics, nil
 }
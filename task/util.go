@@ -1,29 +1,157 @@
 package task
 
 import (
+	"time"
+
 	"github.com/Ankr-network/dccn-daemon/task/kube"
+	"github.com/Ankr-network/dccn-daemon/types"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type Tasker struct {
 	client *kube.Client
 	ns     string
 	host   string
+	issuer kube.CertIssuer
+
+	serviceType corev1.ServiceType
+	nodePorts   *nodePorts
+
+	readyTimeout   time.Duration
+	multiImageMode string
+	policy         *SecurityPolicy
+	nonRoot        bool
+	imagePolicy    *ImagePolicy
+	reserver       Reserver
 }
 
+// Modes of running the images of a task
+const (
+	// one deployment per image
+	MultiImageDeployments = "deployments"
+	// containers of one pod
+	MultiImagePod = "pod"
+)
+
 func NewTasker(cfgpath, namespace, ingressHost string) (*Tasker, error) {
 	client, err := kube.NewClient(cfgpath)
 	if err != nil {
 		return nil, err
 	}
 	return &Tasker{
-		client: client,
-		ns:     namespace,
-		host:   ingressHost,
+		client:         client,
+		ns:             namespace,
+		host:           ingressHost,
+		serviceType:    corev1.ServiceTypeNodePort,
+		nodePorts:      newNodePorts(30000, 32767),
+		multiImageMode: MultiImageDeployments,
+		policy:         &SecurityPolicy{},
+		imagePolicy:    &ImagePolicy{},
 	}, nil
 }
 
+func (t *Tasker) Namespace() string {
+	return t.ns
+}
+
+// SetIngressClass selects the ingress controller serving the task ingresses
+func (t *Tasker) SetIngressClass(class string) {
+	t.client.SetIngressClass(class)
+}
+
+// SetCertIssuer sets the issuer of the task certificates, tasks asking for
+// TLS without a certificate will fail if no issuer set
+func (t *Tasker) SetCertIssuer(issuer kube.CertIssuer) {
+	t.issuer = issuer
+}
+
+// SetExposeService sets how global TCP/UDP exposes are served, by NodePort
+// services with ports allocated in [min, max] or by LoadBalancer services
+func (t *Tasker) SetExposeService(serviceType corev1.ServiceType, min, max int32) error {
+	switch serviceType {
+	case corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return errors.Errorf("unsupported expose service type: %s", serviceType)
+	}
+	if min <= 0 || min > max {
+		return errors.Errorf("invalid node port range: %d-%d", min, max)
+	}
+
+	t.serviceType = serviceType
+	t.nodePorts = newNodePorts(min, max)
+	return nil
+}
+
+// SetReadyTimeout sets how long WaitReady waits for the deployment replicas
+// to become ready, 0 to return without waiting
+func (t *Tasker) SetReadyTimeout(timeout time.Duration) {
+	t.readyTimeout = timeout
+}
+
+func (t *Tasker) ReadyTimeout() time.Duration {
+	return t.readyTimeout
+}
+
+// SetMultiImageMode sets how the images of a task run if the options do not
+// ask for a pod
+func (t *Tasker) SetMultiImageMode(mode string) error {
+	switch mode {
+	case MultiImageDeployments, MultiImagePod:
+	default:
+		return errors.Errorf("unsupported multi image mode: %s", mode)
+	}
+
+	t.multiImageMode = mode
+	return nil
+}
+
+// podImages moves the images after the first into the sidecars of the pod,
+// if the options or the multi image mode ask for a pod
+func (t *Tasker) podImages(images []string, options *types.ServiceOptions) ([]string, *types.ServiceOptions) {
+	if len(images) <= 1 || (options.GetPod() == nil && t.multiImageMode != MultiImagePod) {
+		return images, options
+	}
+
+	res := &types.ServiceOptions{}
+	if options != nil {
+		*res = *options
+	}
+	pod := &types.PodOptions{}
+	if options.GetPod() != nil {
+		*pod = *options.GetPod()
+	}
+	pod.Sidecars = append(append([]string{}, images[1:]...), pod.Sidecars...)
+	res.Pod = pod
+	return images[:1], res
+}
+
+// taskHost is the host generated for the task under the ingress host
+func (t *Tasker) taskHost(name string) string {
+	return name + "." + t.host
+}
+
+// tlsSecret returns the kube writing the task certificate, nil if the
+// certificate is provided by a tenant uploaded secret
+func (t *Tasker) tlsSecret(service *types.ManifestService, hosts []string,
+	options *types.ServiceOptions) (kube.Kube, error) {
+	tls := options.GetTLS()
+	switch {
+	case tls == nil || tls.SecretName != "":
+		return nil, nil
+	case tls.Cert != "" || tls.Key != "":
+		if tls.Cert == "" || tls.Key == "" {
+			return nil, errors.New("tls certificate and key must be set together")
+		}
+		return kube.NewTLSSecret(t.ns, service, []byte(tls.Cert), []byte(tls.Key)), nil
+	case t.issuer == nil:
+		return nil, errors.New("no certificate issuer for tls")
+	default:
+		return t.issuer.Issue(t.ns, service, hosts)
+	}
+}
+
 func (t *Tasker) updateOrCreate(kubes []kube.Kube) error {
 	rollbacks := make([]func(*kube.Client) error, 0, len(kubes))
 	for i := range kubes {
@@ -58,3 +186,23 @@ func (t *Tasker) rollback(rollbacks []func(*kube.Client) error, err error) error
 	}
 	return errors.WithMessage(err, "rolled back")
 }
+
+// ConfigMap returns the data of the config map written by SaveConfigMap, nil
+// if not found
+func (t *Tasker) ConfigMap(name string) (map[string]string, error) {
+	list := &corev1.ConfigMapList{}
+	if err := kube.NewConfigMap(t.ns, name, nil).List(t.client, list); err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		if item.Name == name {
+			return item.Data, nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveConfigMap writes the data into the config map
+func (t *Tasker) SaveConfigMap(name string, data map[string]string) error {
+	return t.updateOrCreate([]kube.Kube{kube.NewConfigMap(t.ns, name, data)})
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HubTask is the spec of a task the hub carries as a JSON object in the
+// image of its deployment, job or cronjob, the hub messages have no field
+// for the options
+type HubTask struct {
+	Images  []string        `json:"images"`
+	Options *ServiceOptions `json:"options,omitempty"`
+	// Deployment group won by the provider, <deployment>/<seq>, whose
+	// requirements place the task
+	Order string `json:"order,omitempty"`
+	// Canary of the deployment task updated, instead of the task
+	Canary *HubCanary `json:"canary,omitempty"`
+}
+
+// Actions of the canary of a hub task
+const (
+	// runs the images beside the task, or replaces those of the canary
+	CanaryDeploy = ""
+	// rolls the canary out to the task and removes it
+	CanaryPromote = "promote"
+	// removes the canary
+	CanaryAbort = "abort"
+)
+
+// HubCanary is the canary action of the update of a hub task
+type HubCanary struct {
+	Action string `json:"action,omitempty"`
+	// Percent of the ingress traffic the canary deployed gets
+	Weight int `json:"weight,omitempty"`
+}
+
+// ParseHubTask reads the spec of a hub task from its image, an image which
+// is not a JSON object is the only image of the task, without options. The
+// images of a task of several are listed by its spec, a canary promoted or
+// aborted has none.
+func ParseHubTask(image string) (*HubTask, error) {
+	image = strings.TrimSpace(image)
+	if !strings.HasPrefix(image, "{") {
+		return &HubTask{Images: []string{image}}, nil
+	}
+
+	spec := &HubTask{}
+	if err := json.Unmarshal([]byte(image), spec); err != nil {
+		return nil, errors.Wrap(err, "hub task")
+	}
+	if spec.Canary != nil {
+		switch spec.Canary.Action {
+		case CanaryPromote, CanaryAbort:
+			return spec, nil
+		case CanaryDeploy:
+		default:
+			return nil, errors.Errorf("hub task: unknown canary action %s", spec.Canary.Action)
+		}
+	}
+	if len(spec.Images) == 0 {
+		return nil, errors.New("hub task: no image")
+	}
+	return spec, nil
+}
@@ -3,14 +3,34 @@ package types
 import (
 	"bytes"
 	"strconv"
+	"strings"
 
 	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
 )
 
 func (id DeploymentGroupID) String() string {
 	return id.Deployment.String() + "/" + strconv.FormatUint(id.Seq, 10)
 }
 
+// ParseDeploymentGroupID parses the <deployment>/<seq> of String
+func ParseDeploymentGroupID(s string) (DeploymentGroupID, error) {
+	id := DeploymentGroupID{}
+	i := strings.LastIndex(s, "/")
+	if i < 0 {
+		return id, errors.Errorf("invalid deployment group id %s", s)
+	}
+	if err := id.Deployment.DecodeString(s[:i]); err != nil {
+		return id, errors.Wrapf(err, "invalid deployment group id %s", s)
+	}
+	seq, err := strconv.ParseUint(s[i+1:], 10, 64)
+	if err != nil {
+		return id, errors.Wrapf(err, "invalid deployment group id %s", s)
+	}
+	id.Seq = seq
+	return id, nil
+}
+
 func (id DeploymentGroupID) Path() string {
 	return id.String()
 }
@@ -0,0 +1,418 @@
+package types
+
+import "github.com/pkg/errors"
+
+// ServiceOptions is the tenant side settings of a manifest service, which
+// are not carried by the exchange messages
+type ServiceOptions struct {
+	// Exposes replacing the default HTTP expose
+	Expose []*ManifestServiceExpose `json:"expose,omitempty"`
+
+	// Custom domains served besides the generated task host
+	Domains []string `json:"domains,omitempty"`
+
+	// Ingress path of each expose port, the main expose defaults to "/"
+	Paths map[uint32]string `json:"paths,omitempty"`
+
+	// TLS termination on the task ingress, disabled if nil
+	TLS *TLSOptions `json:"tls,omitempty"`
+
+	// Health checks, a container failing liveness is restarted, and one
+	// failing readiness receives no traffic
+	Liveness  *Probe `json:"liveness,omitempty"`
+	Readiness *Probe `json:"readiness,omitempty"`
+
+	// Command run in the container before it is stopped
+	PreStop []string `json:"preStop,omitempty"`
+	// Seconds between the stop signal and the kill, kubernetes default if nil
+	TerminationGracePeriod *int64 `json:"terminationGracePeriod,omitempty"`
+
+	// Horizontal autoscaling of the deployment, fixed Count replicas if nil
+	Autoscale *AutoscaleOptions `json:"autoscale,omitempty"`
+	// Resources ordered, those of the deployment group ordered for the hub
+	// tasks, the replicas are not checked if empty. The price of a group is
+	// per unit hour.
+	Order []ResourceGroup `json:"order,omitempty"`
+	// Deployment group of the order of the hub task, <deployment>/<seq>, set
+	// by the daemon
+	OrderID string `json:"-"`
+	// Provider attributes the nodes running the service must have, those of
+	// the deployment group ordered for the hub tasks
+	Requirements []ProviderAttribute `json:"requirements,omitempty"`
+
+	// Runs the images of a task as containers of one pod, instead of one
+	// deployment per image
+	Pod *PodOptions `json:"pod,omitempty"`
+
+	// Replacement of the pods on update, rolling update by default
+	Strategy *StrategyOptions `json:"strategy,omitempty"`
+
+	// Hardened containers, with the relaxations allowed by the datacenter
+	// security policy
+	Security *SecurityOptions `json:"security,omitempty"`
+
+	// Requests of the containers apart from their limits, the units are both
+	// if nil
+	Resources *ResourceOptions `json:"resources,omitempty"`
+}
+
+// QoS classes of the pods
+const (
+	QoSGuaranteed = "Guaranteed"
+	QoSBurstable  = "Burstable"
+)
+
+// ResourceOptions sets the requests of the containers apart from their
+// limits, the resource units. Guaranteed pods, the default, request their
+// limits. Burstable pods request the limits divided by the burst factors, and
+// use up to the limits while the nodes have spare resources.
+type ResourceOptions struct {
+	QoS string `json:"qos,omitempty"`
+	// Limit over request of the burstable pods, 1 if not set
+	CPUBurst    float64 `json:"cpuBurst,omitempty"`
+	MemoryBurst float64 `json:"memoryBurst,omitempty"`
+}
+
+// Seccomp profiles of the pods
+const (
+	SeccompRuntimeDefault = "runtime/default"
+	SeccompUnconfined     = "unconfined"
+)
+
+// SecurityOptions hardens the containers, which run as non-root without
+// privilege escalation, capabilities, and with the runtime default seccomp
+// profile unless relaxed. The containers of the tasks without run as the user
+// of their image, see task.Tasker.SetRunAsNonRoot.
+type SecurityOptions struct {
+	// Allows the image to run as root, or as the user if set
+	RunAsRoot bool   `json:"runAsRoot,omitempty"`
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	AllowPrivilegeEscalation bool `json:"allowPrivilegeEscalation,omitempty"`
+	// Capabilities added back, e.g. NET_BIND_SERVICE
+	Capabilities []string `json:"capabilities,omitempty"`
+	// Mounts the root filesystem read-only, a hardening the tenant opts in
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+	// Seccomp profile, runtime/default if empty
+	Seccomp string `json:"seccomp,omitempty"`
+}
+
+// Deployment strategies
+const (
+	StrategyRollingUpdate = "RollingUpdate"
+	StrategyRecreate      = "Recreate"
+)
+
+// StrategyOptions controls the rollout of the updates. MaxSurge and
+// MaxUnavailable are numbers or percents of the replicas, 25% if empty.
+type StrategyOptions struct {
+	Type           string `json:"type,omitempty"`
+	MaxSurge       string `json:"maxSurge,omitempty"`
+	MaxUnavailable string `json:"maxUnavailable,omitempty"`
+}
+
+// PodOptions is the containers sharing the pod of the service image, they
+// share the network namespace and the volumes
+type PodOptions struct {
+	// Images run in order to completion before the containers start
+	Init []string `json:"init,omitempty"`
+	// Images run beside the service image
+	Sidecars []string `json:"sidecars,omitempty"`
+	// Resource unit of the service container followed by the sidecars, the
+	// service unit if missing
+	Units []*ResourceUnit `json:"units,omitempty"`
+	// emptyDir volumes mounted into all the containers, name to mount path
+	Volumes map[string]string `json:"volumes,omitempty"`
+}
+
+// TLSOptions selects the certificate of the task ingress. SecretName refers
+// to a tenant uploaded secret, Cert and Key are written into a secret by the
+// daemon, and if none of them is set the certificate comes from the issuer.
+type TLSOptions struct {
+	SecretName string `json:"secretName,omitempty"`
+
+	// PEM encoded certificate and private key
+	Cert string `json:"cert,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// Probe checks the container health by one of HTTP, TCP or Exec
+type Probe struct {
+	HTTP *HTTPProbe `json:"http,omitempty"`
+	TCP  *TCPProbe  `json:"tcp,omitempty"`
+	Exec []string   `json:"exec,omitempty"`
+
+	// Seconds after the container started before the first check
+	InitialDelay int32 `json:"initialDelay,omitempty"`
+	// Check interval and timeout in seconds
+	Period  int32 `json:"period,omitempty"`
+	Timeout int32 `json:"timeout,omitempty"`
+	// Consecutive failures before the container is considered unhealthy
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// validate checks the probe has exactly one handler
+func (p *Probe) validate() error {
+	if p == nil {
+		return nil
+	}
+	handlers := 0
+	if p.HTTP != nil {
+		handlers++
+	}
+	if p.TCP != nil {
+		handlers++
+	}
+	if len(p.Exec) != 0 {
+		handlers++
+	}
+	if handlers != 1 {
+		return errors.Errorf("probe with %d of the http, tcp and exec handlers, one expected", handlers)
+	}
+	return nil
+}
+
+// HTTPProbe succeeds on any response status in [200, 400)
+type HTTPProbe struct {
+	Path string `json:"path,omitempty"`
+	Port uint32 `json:"port"`
+}
+
+// TCPProbe succeeds if the port accepts connections
+type TCPProbe struct {
+	Port uint32 `json:"port"`
+}
+
+// AutoscaleOptions scales the replicas in [Min, Max] to keep the average
+// utilization of the pods at the targets, in percent of the resource unit
+type AutoscaleOptions struct {
+	Min uint32 `json:"min"`
+	Max uint32 `json:"max"`
+
+	CPU    int32 `json:"cpu,omitempty"`
+	Memory int32 `json:"memory,omitempty"`
+}
+
+func (o *ServiceOptions) GetExpose() []*ManifestServiceExpose {
+	if o != nil {
+		return o.Expose
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetDomains() []string {
+	if o != nil {
+		return o.Domains
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetTLS() *TLSOptions {
+	if o != nil {
+		return o.TLS
+	}
+	return nil
+}
+
+// Path returns the ingress path of the port, empty if not routed
+func (o *ServiceOptions) Path(port uint32) string {
+	if o != nil {
+		return o.Paths[port]
+	}
+	return ""
+}
+
+func (o *ServiceOptions) GetLiveness() *Probe {
+	if o != nil {
+		return o.Liveness
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetReadiness() *Probe {
+	if o != nil {
+		return o.Readiness
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetPreStop() []string {
+	if o != nil {
+		return o.PreStop
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetTerminationGracePeriod() *int64 {
+	if o != nil {
+		return o.TerminationGracePeriod
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetOrderID() string {
+	if o != nil {
+		return o.OrderID
+	}
+	return ""
+}
+
+func (o *ServiceOptions) GetAutoscale() *AutoscaleOptions {
+	if o != nil {
+		return o.Autoscale
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetOrder() []ResourceGroup {
+	if o != nil {
+		return o.Order
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetRequirements() []ProviderAttribute {
+	if o != nil {
+		return o.Requirements
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetPod() *PodOptions {
+	if o != nil {
+		return o.Pod
+	}
+	return nil
+}
+
+func (o *PodOptions) GetInit() []string {
+	if o != nil {
+		return o.Init
+	}
+	return nil
+}
+
+func (o *PodOptions) GetSidecars() []string {
+	if o != nil {
+		return o.Sidecars
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetStrategy() *StrategyOptions {
+	if o != nil {
+		return o.Strategy
+	}
+	return nil
+}
+
+func (o *ServiceOptions) GetSecurity() *SecurityOptions {
+	if o != nil {
+		return o.Security
+	}
+	return nil
+}
+
+// RunsAsRoot reports whether the image may run as root, by RunAsRoot or by
+// the root user
+func (o *SecurityOptions) RunsAsRoot() bool {
+	return o != nil && (o.RunAsRoot || o.RunAsUser != nil && *o.RunAsUser == 0)
+}
+
+func (o *ServiceOptions) GetResources() *ResourceOptions {
+	if o != nil {
+		return o.Resources
+	}
+	return nil
+}
+
+// Burst returns the limit over request of the cpu and the memory, 1 unless
+// the pods are burstable
+func (o *ResourceOptions) Burst() (cpu, memory float64) {
+	if o == nil || o.QoS != QoSBurstable {
+		return 1, 1
+	}
+	cpu, memory = o.CPUBurst, o.MemoryBurst
+	if cpu < 1 {
+		cpu = 1
+	}
+	if memory < 1 {
+		memory = 1
+	}
+	return cpu, memory
+}
+
+// Request is the request of a container limited to the unit, the disk is
+// requested in full
+func (o *ResourceOptions) Request(unit *ResourceUnit) *ResourceUnit {
+	cpu, memory := o.Burst()
+	return &ResourceUnit{
+		CPU:    uint32(float64(unit.CPU) / cpu),
+		Memory: uint64(float64(unit.Memory) / memory),
+		Disk:   unit.Disk,
+	}
+}
+
+// Validate checks the autoscale bounds, the pod units, the strategy, the
+// probes, the qos class, and the most replicas the service can run against
+// the ordered resources fitting its unit
+func (o *ServiceOptions) Validate(service *ManifestService) error {
+	max := service.Count
+	if autoscale := o.GetAutoscale(); autoscale != nil {
+		if autoscale.Min == 0 || autoscale.Min > autoscale.Max {
+			return errors.Errorf("invalid autoscale replicas: %d-%d", autoscale.Min, autoscale.Max)
+		}
+		if autoscale.CPU <= 0 && autoscale.Memory <= 0 {
+			return errors.New("autoscale without cpu or memory target")
+		}
+		max = autoscale.Max
+	}
+	if pod := o.GetPod(); pod != nil && len(pod.Units) > len(pod.Sidecars)+1 {
+		return errors.Errorf("%d units for %d containers", len(pod.Units), len(pod.Sidecars)+1)
+	}
+	if strategy := o.GetStrategy(); strategy != nil {
+		switch strategy.Type {
+		case "", StrategyRollingUpdate:
+		case StrategyRecreate:
+			if strategy.MaxSurge != "" || strategy.MaxUnavailable != "" {
+				return errors.New("recreate strategy with rolling update parameters")
+			}
+		default:
+			return errors.Errorf("unsupported strategy: %s", strategy.Type)
+		}
+	}
+	if err := o.GetLiveness().validate(); err != nil {
+		return errors.Wrap(err, "liveness")
+	}
+	if err := o.GetReadiness().validate(); err != nil {
+		return errors.Wrap(err, "readiness")
+	}
+	if resources := o.GetResources(); resources != nil {
+		switch resources.QoS {
+		case "", QoSGuaranteed:
+			if resources.CPUBurst > 1 || resources.MemoryBurst > 1 {
+				return errors.New("guaranteed pods with burst factors")
+			}
+		case QoSBurstable:
+			if resources.CPUBurst <= 1 && resources.MemoryBurst <= 1 {
+				return errors.New("burstable pods without a burst factor over 1")
+			}
+		default:
+			return errors.Errorf("unsupported qos class: %s", resources.QoS)
+		}
+	}
+	if o == nil || len(o.Order) == 0 {
+		return nil
+	}
+
+	var ordered uint32
+	for _, group := range o.Order {
+		if service.Unit == nil || group.Unit.Fits(service.Unit) {
+			ordered += group.Count
+		}
+	}
+	if max > ordered {
+		return errors.Errorf("%d replicas exceed the %d ordered", max, ordered)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceOptions_ValidateProbes(t *testing.T) {
+	service := types.NewManifestService("web", "nginx")
+
+	options := &types.ServiceOptions{Readiness: &types.Probe{HTTP: &types.HTTPProbe{Path: "/", Port: 80}}}
+	assert.NoError(t, options.Validate(service))
+
+	// no handler, the api server rejects an exec probe without a command
+	options = &types.ServiceOptions{Liveness: &types.Probe{InitialDelay: 10}}
+	assert.Error(t, options.Validate(service))
+
+	options = &types.ServiceOptions{Readiness: &types.Probe{
+		TCP:  &types.TCPProbe{Port: 80},
+		Exec: []string{"true"},
+	}}
+	assert.Error(t, options.Validate(service))
+}
+
+func TestIsTaskService(t *testing.T) {
+	assert.True(t, types.IsTaskService("web", "web"))
+	assert.True(t, types.IsTaskService("web", "web-1"))
+	assert.False(t, types.IsTaskService("web", "web-canary"))
+	assert.False(t, types.IsTaskService("web", "webapp"))
+	assert.False(t, types.IsTaskService("web", "web-"))
+}
+
+func TestSecurityOptions_RunsAsRoot(t *testing.T) {
+	root, user := int64(0), int64(1000)
+	assert.False(t, (*types.SecurityOptions)(nil).RunsAsRoot())
+	assert.False(t, (&types.SecurityOptions{RunAsUser: &user}).RunsAsRoot())
+	assert.True(t, (&types.SecurityOptions{RunAsRoot: true}).RunsAsRoot())
+	// the root user without RunAsRoot
+	assert.True(t, (&types.SecurityOptions{RunAsUser: &root}).RunsAsRoot())
+}
+
+func TestParseHubTask(t *testing.T) {
+	spec, err := types.ParseHubTask("nginx:1.12")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx:1.12"}, spec.Images)
+	assert.Nil(t, spec.Options)
+	// the images are not split implicitly
+	spec, err = types.ParseHubTask("nginx:1.12,busybox")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx:1.12,busybox"}, spec.Images)
+
+	spec, err = types.ParseHubTask(`{"images": ["nginx:1.12", "busybox"], "options": {"domains": ["www.example.com"]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx:1.12", "busybox"}, spec.Images)
+	assert.Equal(t, []string{"www.example.com"}, spec.Options.Domains)
+
+	_, err = types.ParseHubTask(`{"options": {}}`)
+	assert.Error(t, err)
+
+	spec, err = types.ParseHubTask(`{"images": ["nginx:1.13"], "canary": {"weight": 10}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.HubCanary{Action: types.CanaryDeploy, Weight: 10}, spec.Canary)
+	spec, err = types.ParseHubTask(`{"canary": {"action": "promote"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, types.CanaryPromote, spec.Canary.Action)
+	_, err = types.ParseHubTask(`{"canary": {}}`)
+	assert.Error(t, err)
+	_, err = types.ParseHubTask(`{"canary": {"action": "resume"}}`)
+	assert.Error(t, err)
+	_, err = types.ParseHubTask(`{"images": `)
+	assert.Error(t, err)
+}
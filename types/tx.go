@@ -0,0 +1,105 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/types/base"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// TxVersion is the version of the envelopes written, the envelopes of other
+// versions are rejected on decoding
+const TxVersion = 1
+
+// Key is the chain key of the datacenter namespace
+func (m *TxEnvelope) Key() string {
+	return m.DataCenter + ":" + m.Namespace
+}
+
+// Period returns the period of the payload
+func (m *TxEnvelope) Period() (start, end time.Time) {
+	return time.Unix(0, m.PeriodStart), time.Unix(0, m.PeriodEnd)
+}
+
+// SetPeriod sets the period of the payload
+func (m *TxEnvelope) SetPeriod(start, end time.Time) {
+	m.PeriodStart, m.PeriodEnd = start.UnixNano(), end.UnixNano()
+}
+
+// Address is the provider address of the public key
+func Address(pub crypto.PubKey) base.Bytes {
+	return base.Bytes(pub.Address())
+}
+
+// signBytes is the envelope without the signature
+func (m *TxEnvelope) signBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return unsigned.Marshal()
+}
+
+// Sign sets the public key and signs the envelope with the ed25519 provider key
+func (m *TxEnvelope) Sign(key ed25519.PrivKeyEd25519) error {
+	pub := key.PubKey().(ed25519.PubKeyEd25519)
+	m.PubKey = pub[:]
+
+	data, err := m.signBytes()
+	if err != nil {
+		return errors.Wrap(err, "sign tx")
+	}
+	m.Signature, err = key.Sign(data)
+	return errors.Wrap(err, "sign tx")
+}
+
+// Address is the provider address of the signing key, nil if the key is
+// malformed
+func (m *TxEnvelope) Address() base.Bytes {
+	if len(m.PubKey) != ed25519.PubKeyEd25519Size {
+		return nil
+	}
+	var pub ed25519.PubKeyEd25519
+	copy(pub[:], m.PubKey)
+	return Address(pub)
+}
+
+// Verify checks the signature, the provider address of the key must be the
+// address if not empty
+func (m *TxEnvelope) Verify(address base.Bytes) error {
+	if len(m.PubKey) != ed25519.PubKeyEd25519Size {
+		return errors.Errorf("public key of %d bytes", len(m.PubKey))
+	}
+	var pub ed25519.PubKeyEd25519
+	copy(pub[:], m.PubKey)
+
+	if len(address) != 0 && !Address(pub).Equal(address) {
+		return errors.Errorf("signed by %s, not %s", Address(pub), address)
+	}
+	data, err := m.signBytes()
+	if err != nil {
+		return errors.Wrap(err, "verify tx")
+	}
+	if !pub.VerifyBytes(data, m.Signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// EncodeTx marshals the envelope into the chain tx
+func EncodeTx(m *TxEnvelope) ([]byte, error) {
+	data, err := m.Marshal()
+	return data, errors.Wrap(err, "encode tx")
+}
+
+// DecodeTx unmarshals the chain tx of the current version
+func DecodeTx(data []byte) (*TxEnvelope, error) {
+	m := &TxEnvelope{}
+	if err := m.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(err, "decode tx")
+	}
+	if m.Version != TxVersion {
+		return nil, errors.Errorf("decode tx: version %d not supported", m.Version)
+	}
+	return m, nil
+}
@@ -0,0 +1,43 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-daemon/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestTxEnvelope(t *testing.T) {
+	key := ed25519.GenPrivKey()
+	tx := &types.TxEnvelope{
+		Type:       types.TxEnvelope_METERING,
+		Version:    types.TxVersion,
+		DataCenter: "dc",
+		Namespace:  "default",
+		Seq:        300,
+		Payload:    []byte(`[{"tasks":{}}]`),
+	}
+	tx.SetPeriod(time.Unix(1550000000, 0), time.Unix(1550000030, 0))
+	require.NoError(t, tx.Sign(key))
+
+	data, err := types.EncodeTx(tx)
+	require.NoError(t, err)
+	decoded, err := types.DecodeTx(data)
+	require.NoError(t, err)
+	assert.Equal(t, tx, decoded)
+	assert.Equal(t, "dc:default", decoded.Key())
+	require.NoError(t, decoded.Verify(types.Address(key.PubKey())))
+
+	assert.Error(t, decoded.Verify(types.Address(ed25519.GenPrivKey().PubKey())))
+	decoded.Seq++
+	assert.Error(t, decoded.Verify(nil))
+
+	tx.Version++
+	data, err = types.EncodeTx(tx)
+	require.NoError(t, err)
+	_, err = types.DecodeTx(data)
+	assert.Error(t, err)
+}
@@ -55,7 +55,7 @@ func (x DeploymentGroup_DeploymentGroupState) String() string {
 	return proto.EnumName(DeploymentGroup_DeploymentGroupState_name, int32(x))
 }
 func (DeploymentGroup_DeploymentGroupState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{5, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{5, 0}
 }
 
 type Deployment_DeploymentState int32
@@ -78,7 +78,7 @@ func (x Deployment_DeploymentState) String() string {
 	return proto.EnumName(Deployment_DeploymentState_name, int32(x))
 }
 func (Deployment_DeploymentState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{7, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{7, 0}
 }
 
 type TxCloseDeployment_ReasonCode int32
@@ -104,7 +104,42 @@ func (x TxCloseDeployment_ReasonCode) String() string {
 	return proto.EnumName(TxCloseDeployment_ReasonCode_name, int32(x))
 }
 func (TxCloseDeployment_ReasonCode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{11, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{11, 0}
+}
+
+type TxEnvelope_Type int32
+
+const (
+	TxEnvelope_UNKNOWN           TxEnvelope_Type = 0
+	TxEnvelope_METERING          TxEnvelope_Type = 1
+	TxEnvelope_CREATE_DEPLOYMENT TxEnvelope_Type = 2
+	TxEnvelope_CLOSE_DEPLOYMENT  TxEnvelope_Type = 3
+	TxEnvelope_CREATE_BID        TxEnvelope_Type = 4
+	TxEnvelope_REGISTER_PROVIDER TxEnvelope_Type = 5
+)
+
+var TxEnvelope_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "METERING",
+	2: "CREATE_DEPLOYMENT",
+	3: "CLOSE_DEPLOYMENT",
+	4: "CREATE_BID",
+	5: "REGISTER_PROVIDER",
+}
+var TxEnvelope_Type_value = map[string]int32{
+	"UNKNOWN":           0,
+	"METERING":          1,
+	"CREATE_DEPLOYMENT": 2,
+	"CLOSE_DEPLOYMENT":  3,
+	"CREATE_BID":        4,
+	"REGISTER_PROVIDER": 5,
+}
+
+func (x TxEnvelope_Type) String() string {
+	return proto.EnumName(TxEnvelope_Type_name, int32(x))
+}
+func (TxEnvelope_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_types_401285c03e2a8771, []int{12, 0}
 }
 
 // BEGIN EXCHANGE
@@ -121,7 +156,7 @@ func (m *ResourceUnit) Reset()         { *m = ResourceUnit{} }
 func (m *ResourceUnit) String() string { return proto.CompactTextString(m) }
 func (*ResourceUnit) ProtoMessage()    {}
 func (*ResourceUnit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{0}
+	return fileDescriptor_types_401285c03e2a8771, []int{0}
 }
 func (m *ResourceUnit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -180,7 +215,7 @@ func (m *ResourceGroup) Reset()         { *m = ResourceGroup{} }
 func (m *ResourceGroup) String() string { return proto.CompactTextString(m) }
 func (*ResourceGroup) ProtoMessage()    {}
 func (*ResourceGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{1}
+	return fileDescriptor_types_401285c03e2a8771, []int{1}
 }
 func (m *ResourceGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -238,7 +273,7 @@ func (m *ProviderAttribute) Reset()         { *m = ProviderAttribute{} }
 func (m *ProviderAttribute) String() string { return proto.CompactTextString(m) }
 func (*ProviderAttribute) ProtoMessage()    {}
 func (*ProviderAttribute) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{2}
+	return fileDescriptor_types_401285c03e2a8771, []int{2}
 }
 func (m *ProviderAttribute) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -290,7 +325,7 @@ func (m *GroupSpec) Reset()         { *m = GroupSpec{} }
 func (m *GroupSpec) String() string { return proto.CompactTextString(m) }
 func (*GroupSpec) ProtoMessage()    {}
 func (*GroupSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{3}
+	return fileDescriptor_types_401285c03e2a8771, []int{3}
 }
 func (m *GroupSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -349,7 +384,7 @@ type DeploymentGroupID struct {
 func (m *DeploymentGroupID) Reset()      { *m = DeploymentGroupID{} }
 func (*DeploymentGroupID) ProtoMessage() {}
 func (*DeploymentGroupID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{4}
+	return fileDescriptor_types_401285c03e2a8771, []int{4}
 }
 func (m *DeploymentGroupID) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -398,7 +433,7 @@ func (m *DeploymentGroup) Reset()         { *m = DeploymentGroup{} }
 func (m *DeploymentGroup) String() string { return proto.CompactTextString(m) }
 func (*DeploymentGroup) ProtoMessage()    {}
 func (*DeploymentGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{5}
+	return fileDescriptor_types_401285c03e2a8771, []int{5}
 }
 func (m *DeploymentGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -469,7 +504,7 @@ func (m *DeploymentGroups) Reset()         { *m = DeploymentGroups{} }
 func (m *DeploymentGroups) String() string { return proto.CompactTextString(m) }
 func (*DeploymentGroups) ProtoMessage()    {}
 func (*DeploymentGroups) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{6}
+	return fileDescriptor_types_401285c03e2a8771, []int{6}
 }
 func (m *DeploymentGroups) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -516,7 +551,7 @@ func (m *Deployment) Reset()         { *m = Deployment{} }
 func (m *Deployment) String() string { return proto.CompactTextString(m) }
 func (*Deployment) ProtoMessage()    {}
 func (*Deployment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{7}
+	return fileDescriptor_types_401285c03e2a8771, []int{7}
 }
 func (m *Deployment) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -559,7 +594,7 @@ func (m *Deployments) Reset()         { *m = Deployments{} }
 func (m *Deployments) String() string { return proto.CompactTextString(m) }
 func (*Deployments) ProtoMessage()    {}
 func (*Deployments) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{8}
+	return fileDescriptor_types_401285c03e2a8771, []int{8}
 }
 func (m *Deployments) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -606,7 +641,7 @@ func (m *TxCreateDeployment) Reset()         { *m = TxCreateDeployment{} }
 func (m *TxCreateDeployment) String() string { return proto.CompactTextString(m) }
 func (*TxCreateDeployment) ProtoMessage()    {}
 func (*TxCreateDeployment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{9}
+	return fileDescriptor_types_401285c03e2a8771, []int{9}
 }
 func (m *TxCreateDeployment) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -664,7 +699,7 @@ func (m *TxUpdateDeployment) Reset()         { *m = TxUpdateDeployment{} }
 func (m *TxUpdateDeployment) String() string { return proto.CompactTextString(m) }
 func (*TxUpdateDeployment) ProtoMessage()    {}
 func (*TxUpdateDeployment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{10}
+	return fileDescriptor_types_401285c03e2a8771, []int{10}
 }
 func (m *TxUpdateDeployment) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -702,7 +737,7 @@ func (m *TxCloseDeployment) Reset()         { *m = TxCloseDeployment{} }
 func (m *TxCloseDeployment) String() string { return proto.CompactTextString(m) }
 func (*TxCloseDeployment) ProtoMessage()    {}
 func (*TxCloseDeployment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{11}
+	return fileDescriptor_types_401285c03e2a8771, []int{11}
 }
 func (m *TxCloseDeployment) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -734,6 +769,111 @@ func (m *TxCloseDeployment) GetReason() TxCloseDeployment_ReasonCode {
 	return TxCloseDeployment_UNSET
 }
 
+// Datacenter write to the chain, signed by the provider key
+type TxEnvelope struct {
+	Type       TxEnvelope_Type `protobuf:"varint,1,opt,name=type,proto3,enum=types.TxEnvelope_Type" json:"type,omitempty"`
+	Version    uint32          `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	DataCenter string          `protobuf:"bytes,3,opt,name=dataCenter,proto3" json:"dataCenter,omitempty"`
+	Namespace  string          `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// period of the payload in unix nanoseconds
+	PeriodStart int64 `protobuf:"varint,5,opt,name=periodStart,proto3" json:"periodStart,omitempty"`
+	PeriodEnd   int64 `protobuf:"varint,6,opt,name=periodEnd,proto3" json:"periodEnd,omitempty"`
+	// sequence of the writes of the datacenter namespace, the retries of a
+	// write have the same one
+	Seq                  uint64                                               `protobuf:"varint,7,opt,name=seq,proto3" json:"seq,omitempty"`
+	Payload              []byte                                               `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	PubKey               github_com_Ankr_network_dccn_daemon_types_base.Bytes `protobuf:"bytes,9,opt,name=pubKey,proto3,customtype=github.com/Ankr-network/dccn-daemon/types/base.Bytes" json:"pubKey"`
+	Signature            github_com_Ankr_network_dccn_daemon_types_base.Bytes `protobuf:"bytes,10,opt,name=signature,proto3,customtype=github.com/Ankr-network/dccn-daemon/types/base.Bytes" json:"signature"`
+	XXX_NoUnkeyedLiteral struct{}                                             `json:"-"`
+	XXX_unrecognized     []byte                                               `json:"-"`
+	XXX_sizecache        int32                                                `json:"-"`
+}
+
+func (m *TxEnvelope) Reset()         { *m = TxEnvelope{} }
+func (m *TxEnvelope) String() string { return proto.CompactTextString(m) }
+func (*TxEnvelope) ProtoMessage()    {}
+func (*TxEnvelope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_types_401285c03e2a8771, []int{12}
+}
+func (m *TxEnvelope) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxEnvelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalTo(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (dst *TxEnvelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxEnvelope.Merge(dst, src)
+}
+func (m *TxEnvelope) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxEnvelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxEnvelope.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxEnvelope proto.InternalMessageInfo
+
+func (m *TxEnvelope) GetType() TxEnvelope_Type {
+	if m != nil {
+		return m.Type
+	}
+	return TxEnvelope_UNKNOWN
+}
+
+func (m *TxEnvelope) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *TxEnvelope) GetDataCenter() string {
+	if m != nil {
+		return m.DataCenter
+	}
+	return ""
+}
+
+func (m *TxEnvelope) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *TxEnvelope) GetPeriodStart() int64 {
+	if m != nil {
+		return m.PeriodStart
+	}
+	return 0
+}
+
+func (m *TxEnvelope) GetPeriodEnd() int64 {
+	if m != nil {
+		return m.PeriodEnd
+	}
+	return 0
+}
+
+func (m *TxEnvelope) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *TxEnvelope) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
 type ManifestRequest struct {
 	Key                  github_com_Ankr_network_dccn_daemon_types_base.Bytes `protobuf:"bytes,1,opt,name=key,proto3,customtype=github.com/Ankr-network/dccn-daemon/types/base.Bytes" json:"key"`
 	Signature            github_com_Ankr_network_dccn_daemon_types_base.Bytes `protobuf:"bytes,2,opt,name=signature,proto3,customtype=github.com/Ankr-network/dccn-daemon/types/base.Bytes" json:"signature"`
@@ -748,7 +888,7 @@ func (m *ManifestRequest) Reset()         { *m = ManifestRequest{} }
 func (m *ManifestRequest) String() string { return proto.CompactTextString(m) }
 func (*ManifestRequest) ProtoMessage()    {}
 func (*ManifestRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{12}
+	return fileDescriptor_types_401285c03e2a8771, []int{13}
 }
 func (m *ManifestRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -791,7 +931,7 @@ func (m *Manifest) Reset()         { *m = Manifest{} }
 func (m *Manifest) String() string { return proto.CompactTextString(m) }
 func (*Manifest) ProtoMessage()    {}
 func (*Manifest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{13}
+	return fileDescriptor_types_401285c03e2a8771, []int{14}
 }
 func (m *Manifest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -837,7 +977,7 @@ func (m *ManifestGroup) Reset()         { *m = ManifestGroup{} }
 func (m *ManifestGroup) String() string { return proto.CompactTextString(m) }
 func (*ManifestGroup) ProtoMessage()    {}
 func (*ManifestGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{14}
+	return fileDescriptor_types_401285c03e2a8771, []int{15}
 }
 func (m *ManifestGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -898,7 +1038,7 @@ func (m *ManifestService) Reset()         { *m = ManifestService{} }
 func (m *ManifestService) String() string { return proto.CompactTextString(m) }
 func (*ManifestService) ProtoMessage()    {}
 func (*ManifestService) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{15}
+	return fileDescriptor_types_401285c03e2a8771, []int{16}
 }
 func (m *ManifestService) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -989,7 +1129,7 @@ func (m *ManifestServiceExpose) Reset()         { *m = ManifestServiceExpose{} }
 func (m *ManifestServiceExpose) String() string { return proto.CompactTextString(m) }
 func (*ManifestServiceExpose) ProtoMessage()    {}
 func (*ManifestServiceExpose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{16}
+	return fileDescriptor_types_401285c03e2a8771, []int{17}
 }
 func (m *ManifestServiceExpose) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1066,7 +1206,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{17}
+	return fileDescriptor_types_401285c03e2a8771, []int{18}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1104,7 +1244,7 @@ func (m *Version) Reset()         { *m = Version{} }
 func (m *Version) String() string { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()    {}
 func (*Version) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{18}
+	return fileDescriptor_types_401285c03e2a8771, []int{19}
 }
 func (m *Version) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1165,7 +1305,7 @@ func (m *ServerStatus) Reset()         { *m = ServerStatus{} }
 func (m *ServerStatus) String() string { return proto.CompactTextString(m) }
 func (*ServerStatus) ProtoMessage()    {}
 func (*ServerStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{19}
+	return fileDescriptor_types_401285c03e2a8771, []int{20}
 }
 func (m *ServerStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1233,7 +1373,7 @@ func (m *ServerStatusParseable) Reset()         { *m = ServerStatusParseable{} }
 func (m *ServerStatusParseable) String() string { return proto.CompactTextString(m) }
 func (*ServerStatusParseable) ProtoMessage()    {}
 func (*ServerStatusParseable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20}
+	return fileDescriptor_types_401285c03e2a8771, []int{21}
 }
 func (m *ServerStatusParseable) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1299,7 +1439,7 @@ func (m *ServerStatusParseable_ProviderStatus) Reset()         { *m = ServerStat
 func (m *ServerStatusParseable_ProviderStatus) String() string { return proto.CompactTextString(m) }
 func (*ServerStatusParseable_ProviderStatus) ProtoMessage()    {}
 func (*ServerStatusParseable_ProviderStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{21, 0}
 }
 func (m *ServerStatusParseable_ProviderStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1360,7 +1500,7 @@ func (m *ServerStatusParseable_ProviderClusterStatus) String() string {
 }
 func (*ServerStatusParseable_ProviderClusterStatus) ProtoMessage() {}
 func (*ServerStatusParseable_ProviderClusterStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20, 1}
+	return fileDescriptor_types_401285c03e2a8771, []int{21, 1}
 }
 func (m *ServerStatusParseable_ProviderClusterStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1408,7 +1548,7 @@ func (m *ServerStatusParseable_ProviderInventoryStatus) String() string {
 }
 func (*ServerStatusParseable_ProviderInventoryStatus) ProtoMessage() {}
 func (*ServerStatusParseable_ProviderInventoryStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20, 2}
+	return fileDescriptor_types_401285c03e2a8771, []int{21, 2}
 }
 func (m *ServerStatusParseable_ProviderInventoryStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1464,7 +1604,7 @@ func (m *ServerStatusParseable_ProviderInventoryStatus_ResourceUnit) String() st
 }
 func (*ServerStatusParseable_ProviderInventoryStatus_ResourceUnit) ProtoMessage() {}
 func (*ServerStatusParseable_ProviderInventoryStatus_ResourceUnit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20, 2, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{21, 2, 0}
 }
 func (m *ServerStatusParseable_ProviderInventoryStatus_ResourceUnit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1526,7 +1666,7 @@ func (m *ServerStatusParseable_ProviderInventoryStatus_Reservations) String() st
 }
 func (*ServerStatusParseable_ProviderInventoryStatus_Reservations) ProtoMessage() {}
 func (*ServerStatusParseable_ProviderInventoryStatus_Reservations) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{20, 2, 1}
+	return fileDescriptor_types_401285c03e2a8771, []int{21, 2, 1}
 }
 func (m *ServerStatusParseable_ProviderInventoryStatus_Reservations) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1578,7 +1718,7 @@ func (m *ProviderStatus) Reset()         { *m = ProviderStatus{} }
 func (m *ProviderStatus) String() string { return proto.CompactTextString(m) }
 func (*ProviderStatus) ProtoMessage()    {}
 func (*ProviderStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{21}
+	return fileDescriptor_types_401285c03e2a8771, []int{22}
 }
 func (m *ProviderStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1635,7 +1775,7 @@ func (m *ProviderManifestStatus) Reset()         { *m = ProviderManifestStatus{}
 func (m *ProviderManifestStatus) String() string { return proto.CompactTextString(m) }
 func (*ProviderManifestStatus) ProtoMessage()    {}
 func (*ProviderManifestStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{22}
+	return fileDescriptor_types_401285c03e2a8771, []int{23}
 }
 func (m *ProviderManifestStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1678,7 +1818,7 @@ func (m *ProviderBidengineStatus) Reset()         { *m = ProviderBidengineStatus
 func (m *ProviderBidengineStatus) String() string { return proto.CompactTextString(m) }
 func (*ProviderBidengineStatus) ProtoMessage()    {}
 func (*ProviderBidengineStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{23}
+	return fileDescriptor_types_401285c03e2a8771, []int{24}
 }
 func (m *ProviderBidengineStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1721,7 +1861,7 @@ func (m *ProviderClusterStatus) Reset()         { *m = ProviderClusterStatus{} }
 func (m *ProviderClusterStatus) String() string { return proto.CompactTextString(m) }
 func (*ProviderClusterStatus) ProtoMessage()    {}
 func (*ProviderClusterStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{24}
+	return fileDescriptor_types_401285c03e2a8771, []int{25}
 }
 func (m *ProviderClusterStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1765,7 +1905,7 @@ func (m *ProviderInventoryStatus) Reset()         { *m = ProviderInventoryStatus
 func (m *ProviderInventoryStatus) String() string { return proto.CompactTextString(m) }
 func (*ProviderInventoryStatus) ProtoMessage()    {}
 func (*ProviderInventoryStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{25}
+	return fileDescriptor_types_401285c03e2a8771, []int{26}
 }
 func (m *ProviderInventoryStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1817,7 +1957,7 @@ func (m *ProviderInventoryStatus_Resource) Reset()         { *m = ProviderInvent
 func (m *ProviderInventoryStatus_Resource) String() string { return proto.CompactTextString(m) }
 func (*ProviderInventoryStatus_Resource) ProtoMessage()    {}
 func (*ProviderInventoryStatus_Resource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{25, 0}
+	return fileDescriptor_types_401285c03e2a8771, []int{26, 0}
 }
 func (m *ProviderInventoryStatus_Resource) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1875,7 +2015,7 @@ func (m *ProviderInventoryStatus_Reservations) Reset()         { *m = ProviderIn
 func (m *ProviderInventoryStatus_Reservations) String() string { return proto.CompactTextString(m) }
 func (*ProviderInventoryStatus_Reservations) ProtoMessage()    {}
 func (*ProviderInventoryStatus_Reservations) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{25, 1}
+	return fileDescriptor_types_401285c03e2a8771, []int{26, 1}
 }
 func (m *ProviderInventoryStatus_Reservations) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1925,7 +2065,7 @@ func (m *DeployRespone) Reset()         { *m = DeployRespone{} }
 func (m *DeployRespone) String() string { return proto.CompactTextString(m) }
 func (*DeployRespone) ProtoMessage()    {}
 func (*DeployRespone) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{26}
+	return fileDescriptor_types_401285c03e2a8771, []int{27}
 }
 func (m *DeployRespone) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1972,7 +2112,7 @@ func (m *ServiceStatusRequest) Reset()         { *m = ServiceStatusRequest{} }
 func (m *ServiceStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*ServiceStatusRequest) ProtoMessage()    {}
 func (*ServiceStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{27}
+	return fileDescriptor_types_401285c03e2a8771, []int{28}
 }
 func (m *ServiceStatusRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2047,7 +2187,7 @@ func (m *ServiceStatusResponse) Reset()         { *m = ServiceStatusResponse{} }
 func (m *ServiceStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*ServiceStatusResponse) ProtoMessage()    {}
 func (*ServiceStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{28}
+	return fileDescriptor_types_401285c03e2a8771, []int{29}
 }
 func (m *ServiceStatusResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2123,7 +2263,7 @@ func (m *LogRequest) Reset()         { *m = LogRequest{} }
 func (m *LogRequest) String() string { return proto.CompactTextString(m) }
 func (*LogRequest) ProtoMessage()    {}
 func (*LogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{29}
+	return fileDescriptor_types_401285c03e2a8771, []int{30}
 }
 func (m *LogRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2202,7 +2342,7 @@ func (m *LogOptions) Reset()         { *m = LogOptions{} }
 func (m *LogOptions) String() string { return proto.CompactTextString(m) }
 func (*LogOptions) ProtoMessage()    {}
 func (*LogOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{30}
+	return fileDescriptor_types_401285c03e2a8771, []int{31}
 }
 func (m *LogOptions) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2253,7 +2393,7 @@ func (m *Log) Reset()         { *m = Log{} }
 func (m *Log) String() string { return proto.CompactTextString(m) }
 func (*Log) ProtoMessage()    {}
 func (*Log) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{31}
+	return fileDescriptor_types_401285c03e2a8771, []int{32}
 }
 func (m *Log) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2303,7 +2443,7 @@ func (m *LogResponse) Reset()         { *m = LogResponse{} }
 func (m *LogResponse) String() string { return proto.CompactTextString(m) }
 func (*LogResponse) ProtoMessage()    {}
 func (*LogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{32}
+	return fileDescriptor_types_401285c03e2a8771, []int{33}
 }
 func (m *LogResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2349,7 +2489,7 @@ func (m *ServiceStatus) Reset()         { *m = ServiceStatus{} }
 func (m *ServiceStatus) String() string { return proto.CompactTextString(m) }
 func (*ServiceStatus) ProtoMessage()    {}
 func (*ServiceStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{33}
+	return fileDescriptor_types_401285c03e2a8771, []int{34}
 }
 func (m *ServiceStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2412,7 +2552,7 @@ func (m *ManifestGetRequest) Reset()         { *m = ManifestGetRequest{} }
 func (m *ManifestGetRequest) String() string { return proto.CompactTextString(m) }
 func (*ManifestGetRequest) ProtoMessage()    {}
 func (*ManifestGetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{34}
+	return fileDescriptor_types_401285c03e2a8771, []int{35}
 }
 func (m *ManifestGetRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2448,7 +2588,7 @@ func (m *ManifestGetResponse) Reset()         { *m = ManifestGetResponse{} }
 func (m *ManifestGetResponse) String() string { return proto.CompactTextString(m) }
 func (*ManifestGetResponse) ProtoMessage()    {}
 func (*ManifestGetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_aeb7088299649dbb, []int{35}
+	return fileDescriptor_types_401285c03e2a8771, []int{36}
 }
 func (m *ManifestGetResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2493,6 +2633,7 @@ func init() {
 	proto.RegisterType((*TxCreateDeployment)(nil), "types.TxCreateDeployment")
 	proto.RegisterType((*TxUpdateDeployment)(nil), "types.TxUpdateDeployment")
 	proto.RegisterType((*TxCloseDeployment)(nil), "types.TxCloseDeployment")
+	proto.RegisterType((*TxEnvelope)(nil), "types.TxEnvelope")
 	proto.RegisterType((*ManifestRequest)(nil), "types.ManifestRequest")
 	proto.RegisterType((*Manifest)(nil), "types.Manifest")
 	proto.RegisterType((*ManifestGroup)(nil), "types.ManifestGroup")
@@ -2527,6 +2668,7 @@ func init() {
 	proto.RegisterEnum("types.DeploymentGroup_DeploymentGroupState", DeploymentGroup_DeploymentGroupState_name, DeploymentGroup_DeploymentGroupState_value)
 	proto.RegisterEnum("types.Deployment_DeploymentState", Deployment_DeploymentState_name, Deployment_DeploymentState_value)
 	proto.RegisterEnum("types.TxCloseDeployment_ReasonCode", TxCloseDeployment_ReasonCode_name, TxCloseDeployment_ReasonCode_value)
+	proto.RegisterEnum("types.TxEnvelope_Type", TxEnvelope_Type_name, TxEnvelope_Type_value)
 }
 func (this *ResourceUnit) Compare(that interface{}) int {
 	if that == nil {
@@ -2993,6 +3135,28 @@ func (this *TxCloseDeployment) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *TxEnvelope) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&types.TxEnvelope{")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "DataCenter: "+fmt.Sprintf("%#v", this.DataCenter)+",\n")
+	s = append(s, "Namespace: "+fmt.Sprintf("%#v", this.Namespace)+",\n")
+	s = append(s, "PeriodStart: "+fmt.Sprintf("%#v", this.PeriodStart)+",\n")
+	s = append(s, "PeriodEnd: "+fmt.Sprintf("%#v", this.PeriodEnd)+",\n")
+	s = append(s, "Seq: "+fmt.Sprintf("%#v", this.Seq)+",\n")
+	s = append(s, "Payload: "+fmt.Sprintf("%#v", this.Payload)+",\n")
+	s = append(s, "PubKey: "+fmt.Sprintf("%#v", this.PubKey)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	if this.XXX_unrecognized != nil {
+		s = append(s, "XXX_unrecognized:"+fmt.Sprintf("%#v", this.XXX_unrecognized)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ManifestRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4221,6 +4385,86 @@ func (m *TxCloseDeployment) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *TxEnvelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxEnvelope) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+	}
+	if m.Version != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Version))
+	}
+	if len(m.DataCenter) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.DataCenter)))
+		i += copy(dAtA[i:], m.DataCenter)
+	}
+	if len(m.Namespace) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Namespace)))
+		i += copy(dAtA[i:], m.Namespace)
+	}
+	if m.PeriodStart != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.PeriodStart))
+	}
+	if m.PeriodEnd != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.PeriodEnd))
+	}
+	if m.Seq != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Seq))
+	}
+	if len(m.Payload) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
+	}
+	dAtA[i] = 0x4a
+	i++
+	i = encodeVarintTypes(dAtA, i, uint64(m.PubKey.Size()))
+	n12, err := m.PubKey.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n12
+	dAtA[i] = 0x52
+	i++
+	i = encodeVarintTypes(dAtA, i, uint64(m.Signature.Size()))
+	n13, err := m.Signature.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n13
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func (m *ManifestRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4239,36 +4483,36 @@ func (m *ManifestRequest) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0xa
 	i++
 	i = encodeVarintTypes(dAtA, i, uint64(m.Key.Size()))
-	n12, err := m.Key.MarshalTo(dAtA[i:])
+	n14, err := m.Key.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n12
+	i += n14
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintTypes(dAtA, i, uint64(m.Signature.Size()))
-	n13, err := m.Signature.MarshalTo(dAtA[i:])
+	n15, err := m.Signature.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n13
+	i += n15
 	dAtA[i] = 0x1a
 	i++
 	i = encodeVarintTypes(dAtA, i, uint64(m.Deployment.Size()))
-	n14, err := m.Deployment.MarshalTo(dAtA[i:])
+	n16, err := m.Deployment.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n14
+	i += n16
 	if m.Manifest != nil {
 		dAtA[i] = 0x22
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Manifest.Size()))
-		n15, err := m.Manifest.MarshalTo(dAtA[i:])
+		n17, err := m.Manifest.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n15
+		i += n17
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -4409,11 +4653,11 @@ func (m *ManifestService) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x2a
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Unit.Size()))
-		n16, err := m.Unit.MarshalTo(dAtA[i:])
+		n18, err := m.Unit.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n16
+		i += n18
 	}
 	if m.Count != 0 {
 		dAtA[i] = 0x30
@@ -4584,30 +4828,30 @@ func (m *ServerStatus) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0xa
 	i++
 	i = encodeVarintTypes(dAtA, i, uint64(m.Provider.Size()))
-	n17, err := m.Provider.MarshalTo(dAtA[i:])
+	n19, err := m.Provider.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n17
+	i += n19
 	if m.Version != nil {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Version.Size()))
-		n18, err := m.Version.MarshalTo(dAtA[i:])
+		n20, err := m.Version.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n18
+		i += n20
 	}
 	if m.Status != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Status.Size()))
-		n19, err := m.Status.MarshalTo(dAtA[i:])
+		n21, err := m.Status.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n19
+		i += n21
 	}
 	if m.Code != 0 {
 		dAtA[i] = 0x20
@@ -4644,30 +4888,30 @@ func (m *ServerStatusParseable) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0xa
 	i++
 	i = encodeVarintTypes(dAtA, i, uint64(m.Provider.Size()))
-	n20, err := m.Provider.MarshalTo(dAtA[i:])
+	n22, err := m.Provider.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n20
+	i += n22
 	if m.Version != nil {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Version.Size()))
-		n21, err := m.Version.MarshalTo(dAtA[i:])
+		n23, err := m.Version.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n21
+		i += n23
 	}
 	if m.Status != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Status.Size()))
-		n22, err := m.Status.MarshalTo(dAtA[i:])
+		n24, err := m.Status.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n22
+		i += n24
 	}
 	if m.Code != 0 {
 		dAtA[i] = 0x20
@@ -4705,31 +4949,31 @@ func (m *ServerStatusParseable_ProviderStatus) MarshalTo(dAtA []byte) (int, erro
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Cluster.Size()))
-		n23, err := m.Cluster.MarshalTo(dAtA[i:])
+		n25, err := m.Cluster.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n23
+		i += n25
 	}
 	if m.Manifest != nil {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Manifest.Size()))
-		n24, err := m.Manifest.MarshalTo(dAtA[i:])
+		n26, err := m.Manifest.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n24
+		i += n26
 	}
 	if m.Bidengine != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Bidengine.Size()))
-		n25, err := m.Bidengine.MarshalTo(dAtA[i:])
+		n27, err := m.Bidengine.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n25
+		i += n27
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -4756,11 +5000,11 @@ func (m *ServerStatusParseable_ProviderClusterStatus) MarshalTo(dAtA []byte) (in
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Inventory.Size()))
-		n26, err := m.Inventory.MarshalTo(dAtA[i:])
+		n28, err := m.Inventory.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n26
+		i += n28
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -4787,11 +5031,11 @@ func (m *ServerStatusParseable_ProviderInventoryStatus) MarshalTo(dAtA []byte) (
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Reservations.Size()))
-		n27, err := m.Reservations.MarshalTo(dAtA[i:])
+		n29, err := m.Reservations.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n27
+		i += n29
 	}
 	if len(m.Available) > 0 {
 		for _, msg := range m.Available {
@@ -4913,31 +5157,31 @@ func (m *ProviderStatus) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Cluster.Size()))
-		n28, err := m.Cluster.MarshalTo(dAtA[i:])
+		n30, err := m.Cluster.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n28
+		i += n30
 	}
 	if m.Manifest != nil {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Manifest.Size()))
-		n29, err := m.Manifest.MarshalTo(dAtA[i:])
+		n31, err := m.Manifest.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n29
+		i += n31
 	}
 	if m.Bidengine != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Bidengine.Size()))
-		n30, err := m.Bidengine.MarshalTo(dAtA[i:])
+		n32, err := m.Bidengine.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n30
+		i += n32
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -5016,11 +5260,11 @@ func (m *ProviderClusterStatus) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Inventory.Size()))
-		n31, err := m.Inventory.MarshalTo(dAtA[i:])
+		n33, err := m.Inventory.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n31
+		i += n33
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -5047,11 +5291,11 @@ func (m *ProviderInventoryStatus) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Reservations.Size()))
-		n32, err := m.Reservations.MarshalTo(dAtA[i:])
+		n34, err := m.Reservations.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n32
+		i += n34
 	}
 	if len(m.Available) > 0 {
 		for _, msg := range m.Available {
@@ -5325,11 +5569,11 @@ func (m *LogRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x32
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Options.Size()))
-		n33, err := m.Options.MarshalTo(dAtA[i:])
+		n35, err := m.Options.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n33
+		i += n35
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -5425,11 +5669,11 @@ func (m *LogResponse) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Result.Size()))
-		n34, err := m.Result.MarshalTo(dAtA[i:])
+		n36, err := m.Result.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n34
+		i += n36
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -5529,11 +5773,11 @@ func (m *ManifestGetResponse) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Manifest.Size()))
-		n35, err := m.Manifest.MarshalTo(dAtA[i:])
+		n37, err := m.Manifest.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n35
+		i += n37
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
@@ -5774,6 +6018,46 @@ func (m *TxCloseDeployment) Size() (n int) {
 	return n
 }
 
+func (m *TxEnvelope) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	if m.Version != 0 {
+		n += 1 + sovTypes(uint64(m.Version))
+	}
+	l = len(m.DataCenter)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.PeriodStart != 0 {
+		n += 1 + sovTypes(uint64(m.PeriodStart))
+	}
+	if m.PeriodEnd != 0 {
+		n += 1 + sovTypes(uint64(m.PeriodEnd))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovTypes(uint64(m.Seq))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = m.PubKey.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	l = m.Signature.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *ManifestRequest) Size() (n int) {
 	var l int
 	_ = l
@@ -7900,6 +8184,301 @@ func (m *TxCloseDeployment) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *TxEnvelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxEnvelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxEnvelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (TxEnvelope_Type(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataCenter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataCenter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeriodStart", wireType)
+			}
+			m.PeriodStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeriodStart |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeriodEnd", wireType)
+			}
+			m.PeriodEnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeriodEnd |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.PubKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Signature.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ManifestRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -11913,137 +12492,150 @@ var (
 	ErrIntOverflowTypes   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("types/types.proto", fileDescriptor_types_aeb7088299649dbb) }
-
-var fileDescriptor_types_aeb7088299649dbb = []byte{
-	// 2050 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xcd, 0x6f, 0x23, 0x49,
-	0x15, 0x4f, 0xfb, 0xbb, 0x9f, 0xe3, 0xc4, 0xa9, 0xc9, 0x0c, 0xc6, 0x84, 0x24, 0x34, 0x48, 0x64,
-	0xc9, 0x24, 0x66, 0xbc, 0x03, 0xbb, 0x93, 0x8d, 0x04, 0x71, 0xe2, 0x5d, 0x45, 0x0a, 0x49, 0xa8,
-	0x38, 0xc3, 0xc7, 0x22, 0xad, 0xda, 0x76, 0x8d, 0xb7, 0x89, 0xdd, 0xe5, 0xe9, 0x6a, 0x67, 0x27,
-	0x8a, 0x72, 0x00, 0x2e, 0x70, 0x5d, 0x2e, 0x7b, 0x01, 0xc1, 0x1f, 0xc0, 0x91, 0x3f, 0x80, 0x0b,
-	0x1a, 0x71, 0x5a, 0x09, 0x09, 0x56, 0x08, 0x45, 0xcc, 0xc0, 0x01, 0x0d, 0x17, 0xc4, 0x85, 0x0b,
-	0x07, 0x54, 0x1f, 0xfd, 0xe9, 0xce, 0x30, 0x3b, 0xe3, 0x45, 0x73, 0xb1, 0xeb, 0x55, 0xbd, 0xf7,
-	0xea, 0x7d, 0xfd, 0xaa, 0x5e, 0x35, 0xcc, 0xb9, 0x67, 0x43, 0xc2, 0x6a, 0xe2, 0x77, 0x7d, 0xe8,
-	0x50, 0x97, 0xa2, 0xac, 0x20, 0xaa, 0x6b, 0x3d, 0xcb, 0x7d, 0x77, 0xd4, 0x5e, 0xef, 0xd0, 0x41,
-	0xad, 0x47, 0x7b, 0xb4, 0x26, 0x56, 0xdb, 0xa3, 0x7b, 0x82, 0x12, 0x84, 0x18, 0x49, 0xa9, 0xea,
-	0x42, 0x8f, 0xd2, 0x5e, 0x9f, 0xd4, 0xcc, 0xa1, 0x55, 0x33, 0x6d, 0x9b, 0xba, 0xa6, 0x6b, 0x51,
-	0x5b, 0xe9, 0x34, 0xde, 0x86, 0x69, 0x4c, 0x18, 0x1d, 0x39, 0x1d, 0x72, 0x6c, 0x5b, 0x2e, 0xfa,
-	0x34, 0xa4, 0xb7, 0x0f, 0x8f, 0x2b, 0xda, 0xb2, 0xb6, 0x52, 0x6a, 0xe4, 0x9f, 0x5c, 0x2e, 0xa5,
-	0x3b, 0xc3, 0x11, 0xe6, 0x73, 0xe8, 0x06, 0xe4, 0x06, 0x64, 0x40, 0x9d, 0xb3, 0x4a, 0x6a, 0x59,
-	0x5b, 0xc9, 0x60, 0x45, 0x21, 0x04, 0x99, 0xae, 0xc5, 0x4e, 0x2a, 0x69, 0x31, 0x2b, 0xc6, 0x1b,
-	0x99, 0xbf, 0xff, 0x72, 0x49, 0x33, 0x86, 0x50, 0xf2, 0x94, 0xbf, 0xe5, 0xd0, 0xd1, 0x10, 0xad,
-	0x41, 0x66, 0x64, 0x5b, 0xae, 0x50, 0x5f, 0xac, 0x5f, 0x5b, 0x97, 0xde, 0x85, 0x0d, 0x68, 0x64,
-	0x1e, 0x5e, 0x2e, 0x4d, 0x61, 0xc1, 0x86, 0xe6, 0x21, 0xdb, 0xa1, 0x23, 0xdb, 0x15, 0x1b, 0x96,
-	0xb0, 0x24, 0xf8, 0xec, 0xd0, 0xb1, 0x3a, 0x44, 0x6d, 0x28, 0x09, 0xb5, 0xe3, 0x36, 0xcc, 0x1d,
-	0x3a, 0xf4, 0xd4, 0xea, 0x12, 0x67, 0xcb, 0x75, 0x1d, 0xab, 0x3d, 0x72, 0x09, 0x37, 0xd0, 0x36,
-	0x07, 0x44, 0xec, 0xaa, 0x63, 0x31, 0xe6, 0x4a, 0x4e, 0xcd, 0xfe, 0x88, 0x08, 0xd5, 0x3a, 0x96,
-	0x84, 0x52, 0xf2, 0x33, 0x0d, 0x74, 0x61, 0xef, 0xd1, 0x90, 0x74, 0x12, 0xa5, 0x1b, 0x30, 0xed,
-	0x90, 0xfb, 0x23, 0xcb, 0x21, 0x03, 0x62, 0xbb, 0xac, 0x92, 0x5a, 0x4e, 0xaf, 0x14, 0xeb, 0x15,
-	0xe5, 0xcf, 0x98, 0x05, 0xca, 0xa9, 0x88, 0x0c, 0x7a, 0x1d, 0x74, 0x47, 0x39, 0xce, 0x2a, 0x69,
-	0xa1, 0x60, 0x3e, 0x16, 0x10, 0x61, 0x84, 0x12, 0x0e, 0x98, 0x8d, 0x9f, 0x68, 0x30, 0xb7, 0x43,
-	0x86, 0x7d, 0x7a, 0xc6, 0x35, 0x09, 0xa6, 0xdd, 0x1d, 0xf4, 0x3d, 0x80, 0xae, 0x3f, 0x29, 0xac,
-	0x9d, 0x6e, 0x6c, 0x72, 0xd1, 0x3f, 0x5d, 0x2e, 0xdd, 0x0e, 0x95, 0xcc, 0x96, 0x7d, 0xe2, 0xac,
-	0xd9, 0xc4, 0x7d, 0x8f, 0x3a, 0x27, 0xb5, 0x6e, 0xa7, 0x63, 0xaf, 0x75, 0x4d, 0x32, 0xa0, 0xb6,
-	0x2c, 0xb5, 0x5a, 0xdb, 0x64, 0x64, 0xbd, 0x71, 0xe6, 0x12, 0x86, 0x43, 0xfa, 0x50, 0x19, 0xd2,
-	0x8c, 0xdc, 0x57, 0x99, 0xe7, 0xc3, 0x8d, 0xcc, 0x07, 0xbf, 0x58, 0x9a, 0x32, 0xfe, 0x9d, 0x82,
-	0xd9, 0x98, 0x2d, 0xa8, 0x0e, 0x29, 0xab, 0xab, 0x72, 0xec, 0xc5, 0x64, 0xcc, 0xde, 0x46, 0x81,
-	0xdb, 0xf6, 0xe1, 0xe5, 0x92, 0x86, 0x53, 0x56, 0xd7, 0x8f, 0x72, 0x2a, 0x14, 0xe5, 0x2a, 0x14,
-	0xa8, 0xd3, 0x25, 0x4e, 0xab, 0xb5, 0x27, 0x72, 0x9d, 0xc6, 0x3e, 0x8d, 0xb6, 0x20, 0xcb, 0x5c,
-	0xd3, 0x25, 0x95, 0xcc, 0xb2, 0xb6, 0x32, 0x53, 0x5f, 0x4d, 0xde, 0x26, 0x4e, 0x1f, 0x71, 0x11,
-	0x2c, 0x25, 0xc7, 0x92, 0x98, 0x7d, 0xd1, 0x24, 0xe6, 0x3e, 0x4e, 0x12, 0xef, 0xc0, 0x7c, 0x92,
-	0x71, 0xa8, 0x00, 0x99, 0x83, 0xc3, 0xe6, 0x7e, 0x79, 0x0a, 0x15, 0x21, 0x7f, 0x80, 0x77, 0x9a,
-	0xb8, 0xb9, 0x53, 0xd6, 0x10, 0x40, 0x6e, 0x7b, 0xef, 0xe0, 0xa8, 0xb9, 0x53, 0x4e, 0xab, 0x2a,
-	0xfd, 0x3a, 0x94, 0x63, 0x0a, 0x18, 0xba, 0x09, 0x59, 0xcb, 0x25, 0x03, 0x56, 0xd1, 0x84, 0x29,
-	0x37, 0x92, 0xa3, 0x82, 0x25, 0x93, 0xf1, 0x9f, 0x14, 0x40, 0xb0, 0x84, 0xee, 0x42, 0xde, 0xec,
-	0x76, 0x1d, 0xc2, 0xd8, 0x44, 0xaa, 0xc7, 0x53, 0x86, 0x5a, 0x90, 0x73, 0x89, 0x6d, 0x2a, 0x18,
-	0xbf, 0xa8, 0x5a, 0xa5, 0x0b, 0xbd, 0xe6, 0x15, 0x40, 0x5a, 0x14, 0xc0, 0xe7, 0xc6, 0x5c, 0x0d,
-	0x0d, 0x23, 0x69, 0xbf, 0x0b, 0xf9, 0x53, 0xe2, 0x30, 0x8b, 0xda, 0xa2, 0x76, 0x5e, 0xd8, 0x4d,
-	0xa5, 0xcc, 0x78, 0x25, 0x0c, 0x04, 0x99, 0x4b, 0x80, 0xdc, 0xd6, 0x76, 0x6b, 0xf7, 0x6e, 0xb3,
-	0x3c, 0x15, 0x4a, 0x60, 0x4a, 0x25, 0x70, 0x13, 0x8a, 0x81, 0x00, 0x43, 0x6b, 0xd1, 0xdc, 0xcd,
-	0x8d, 0x39, 0xa4, 0x6a, 0x48, 0x25, 0xef, 0xe7, 0x29, 0x40, 0xad, 0x07, 0xdb, 0x0e, 0x31, 0x5d,
-	0x12, 0x4a, 0x62, 0x10, 0x6c, 0x6d, 0x82, 0xc1, 0x9e, 0x87, 0xac, 0x4d, 0xed, 0x0e, 0x51, 0xf8,
-	0x97, 0xc4, 0x53, 0xf1, 0xf9, 0x09, 0x45, 0x19, 0xad, 0x40, 0xae, 0x27, 0x6a, 0x5d, 0xc1, 0xb5,
-	0xac, 0xc2, 0xe4, 0x9f, 0xd7, 0x58, 0xad, 0x1b, 0xbf, 0xd3, 0x78, 0x80, 0x8e, 0x87, 0xdd, 0x68,
-	0x80, 0x3e, 0xd9, 0x63, 0x32, 0xe4, 0x76, 0x6a, 0x92, 0xc5, 0xf5, 0x0f, 0x0d, 0xe6, 0x5a, 0x0f,
-	0xb6, 0xfb, 0x94, 0xfd, 0xff, 0x7c, 0x79, 0x03, 0x72, 0x0e, 0x31, 0x99, 0x72, 0x65, 0xa6, 0xfe,
-	0x79, 0x15, 0xea, 0x31, 0x3b, 0xd6, 0xb1, 0x60, 0xdb, 0xa6, 0x5d, 0x82, 0x95, 0x88, 0xf1, 0x06,
-	0x40, 0x30, 0x8b, 0x74, 0xc8, 0x1e, 0xef, 0x1f, 0x35, 0x5b, 0xe5, 0x29, 0x54, 0x86, 0xe9, 0x56,
-	0x73, 0x7f, 0x6b, 0xbf, 0xf5, 0x8e, 0x80, 0x43, 0x59, 0xe3, 0x33, 0xbb, 0xfb, 0x47, 0xc7, 0x6f,
-	0xbe, 0xb9, 0xbb, 0xbd, 0xdb, 0xdc, 0x6f, 0x95, 0x53, 0xc6, 0xc3, 0x14, 0xcc, 0x7e, 0xc3, 0xb4,
-	0xad, 0x7b, 0x84, 0xb9, 0x98, 0xdc, 0x1f, 0x11, 0xe6, 0xa2, 0x7d, 0x48, 0x9f, 0x90, 0xb3, 0x89,
-	0x38, 0xc9, 0x15, 0xa1, 0xef, 0x82, 0xce, 0xac, 0x9e, 0x6d, 0xba, 0x23, 0x87, 0x4c, 0x24, 0x57,
-	0x81, 0xba, 0x58, 0x5e, 0xd2, 0x13, 0xce, 0xcb, 0x2a, 0x14, 0x06, 0x2a, 0x38, 0x02, 0x5b, 0xc5,
-	0xfa, 0xac, 0xca, 0x8c, 0x1f, 0x33, 0x9f, 0xc1, 0x78, 0x1d, 0x0a, 0xde, 0x2c, 0xba, 0xe9, 0x63,
-	0x47, 0x8b, 0xdc, 0x54, 0x1e, 0x83, 0xbc, 0x1c, 0x3c, 0xfc, 0x7c, 0x0b, 0x4a, 0x91, 0x85, 0xc4,
-	0x46, 0xa8, 0x0e, 0x05, 0x46, 0x9c, 0x53, 0x8b, 0x5f, 0x7f, 0xa9, 0xc8, 0x9d, 0xe3, 0xc9, 0x1e,
-	0xc9, 0x65, 0xec, 0xf3, 0x19, 0x7f, 0xd0, 0x82, 0xec, 0xaa, 0xd5, 0xab, 0x5a, 0x34, 0x6b, 0x60,
-	0xf6, 0xfc, 0x16, 0x4d, 0x10, 0x9c, 0xd3, 0x74, 0x7a, 0xb2, 0x63, 0xd2, 0xb1, 0x18, 0xf3, 0xe6,
-	0x84, 0xd8, 0xa7, 0x95, 0x8c, 0x98, 0xe2, 0x43, 0xf4, 0x45, 0xd5, 0x68, 0x66, 0xaf, 0x6c, 0x34,
-	0xe3, 0x2d, 0x66, 0x2e, 0xdc, 0x62, 0xde, 0x86, 0x1c, 0x79, 0x30, 0xa4, 0x8c, 0x54, 0xf2, 0xc2,
-	0xa9, 0x85, 0x64, 0xa7, 0x9a, 0x82, 0x07, 0x2b, 0x5e, 0xe3, 0x57, 0x1a, 0x5c, 0x4f, 0xe4, 0xe0,
-	0x46, 0x0f, 0xa9, 0x23, 0x21, 0x5a, 0xc2, 0x62, 0x8c, 0x0c, 0x98, 0x26, 0x0f, 0x5c, 0xe2, 0xd8,
-	0x66, 0xff, 0x90, 0xaf, 0xc9, 0x1e, 0x37, 0x32, 0x27, 0x5b, 0x5d, 0xea, 0x52, 0x51, 0x43, 0x3a,
-	0x96, 0x04, 0xaa, 0x40, 0x5e, 0x05, 0x53, 0xe4, 0x5f, 0xc7, 0x1e, 0xc9, 0x5b, 0xf4, 0x5e, 0x9f,
-	0xb6, 0xcd, 0xbe, 0x70, 0xbc, 0x80, 0x15, 0xc5, 0xf5, 0xbc, 0x4b, 0x99, 0x2b, 0x5b, 0x14, 0x1d,
-	0x4b, 0xc2, 0xc8, 0x43, 0xb6, 0x39, 0x18, 0xba, 0x67, 0xc6, 0x01, 0xe4, 0xef, 0xaa, 0xf3, 0xb5,
-	0x12, 0x1c, 0x60, 0x32, 0x17, 0xfe, 0xc9, 0x7b, 0x03, 0x72, 0x1d, 0x3a, 0x18, 0x58, 0xae, 0xca,
-	0x87, 0xa2, 0x44, 0xfb, 0xef, 0xdd, 0xc3, 0x3a, 0x16, 0x63, 0xe3, 0xc7, 0x29, 0x98, 0xe6, 0x11,
-	0x20, 0x0e, 0xbf, 0x08, 0x47, 0x0c, 0x7d, 0x1b, 0x0a, 0x43, 0xd5, 0x50, 0x4d, 0x04, 0xc2, 0xbe,
-	0x36, 0xf4, 0x95, 0xe8, 0x89, 0x5b, 0xac, 0xcf, 0xa8, 0x5c, 0x29, 0x8f, 0x1a, 0xc5, 0x27, 0x97,
-	0x4b, 0x1e, 0x4b, 0xe0, 0xcd, 0x1d, 0xc8, 0x31, 0x61, 0x9a, 0xb0, 0xbb, 0x58, 0xbf, 0x1e, 0x6b,
-	0xfb, 0xa4, 0xdd, 0x0d, 0x78, 0x72, 0xb9, 0xa4, 0x18, 0xb1, 0xfa, 0xe7, 0x0e, 0x77, 0x68, 0x57,
-	0xc6, 0x3e, 0x8b, 0xc5, 0x98, 0x87, 0x6d, 0x40, 0x18, 0xe3, 0xd5, 0x9a, 0x95, 0x61, 0x53, 0xa4,
-	0xf1, 0xaf, 0x02, 0x5c, 0x0f, 0x87, 0xe2, 0xd0, 0x74, 0x18, 0x31, 0xdb, 0x7d, 0xf2, 0xf2, 0xc5,
-	0xe4, 0x20, 0x16, 0x13, 0xaf, 0xa9, 0x4e, 0x34, 0x7f, 0xe2, 0x91, 0xaa, 0xfe, 0x51, 0x83, 0x99,
-	0xa8, 0x52, 0xb4, 0x07, 0xf9, 0x4e, 0x7f, 0xc4, 0x5c, 0x15, 0xa1, 0x62, 0xbd, 0xfe, 0x4c, 0x26,
-	0x6d, 0x4b, 0x19, 0xb9, 0x8c, 0x3d, 0x15, 0xe8, 0x4e, 0xe8, 0xe0, 0x94, 0x71, 0xf9, 0x6c, 0x2c,
-	0xeb, 0x3e, 0x7a, 0xa5, 0xa4, 0xcf, 0x8e, 0x36, 0x41, 0x6f, 0x5b, 0x5d, 0x62, 0xf7, 0x2c, 0x9b,
-	0xa8, 0xe8, 0x2c, 0xc6, 0x64, 0x1b, 0xde, 0xba, 0x12, 0x0e, 0x04, 0xaa, 0x27, 0x70, 0x3d, 0xd1,
-	0x34, 0x84, 0x41, 0xb7, 0xec, 0x53, 0x62, 0xbb, 0xfc, 0x55, 0x2d, 0x3d, 0xbc, 0xfd, 0x4c, 0x1e,
-	0xee, 0x7a, 0x52, 0xde, 0x66, 0xbe, 0x9a, 0xea, 0x0f, 0x32, 0xf0, 0xa9, 0x2b, 0xd8, 0x10, 0xe1,
-	0x4f, 0x1e, 0x7e, 0x58, 0xc8, 0x6f, 0x00, 0x6a, 0xcb, 0xad, 0xe7, 0xd9, 0x92, 0x1f, 0xa6, 0xbe,
-	0x22, 0x1c, 0x51, 0x8b, 0xde, 0x01, 0xdd, 0x3c, 0x35, 0xad, 0x3e, 0x97, 0x57, 0xd7, 0xc2, 0x73,
-	0xef, 0x11, 0x1c, 0xd8, 0x81, 0xce, 0xea, 0xf1, 0xf3, 0x7e, 0xb5, 0xd0, 0x13, 0xbf, 0x5a, 0xe8,
-	0xf2, 0xab, 0x45, 0xf5, 0xb7, 0x9a, 0xd0, 0x1b, 0x38, 0xf2, 0x1d, 0xc8, 0x99, 0x1d, 0xd7, 0x3a,
-	0x25, 0xea, 0xc6, 0x9c, 0x80, 0x17, 0x4a, 0x21, 0x7a, 0x1b, 0xf2, 0x43, 0x62, 0x77, 0x2d, 0xbb,
-	0x37, 0xb9, 0x08, 0x79, 0x1a, 0x8d, 0xdf, 0x8c, 0x43, 0xe9, 0xab, 0x71, 0x28, 0x2d, 0xc4, 0xea,
-	0xf7, 0x25, 0x03, 0x8d, 0xb1, 0x01, 0x37, 0x92, 0x77, 0x40, 0xcb, 0x50, 0x0c, 0xda, 0x21, 0xa6,
-	0x2e, 0xd5, 0xf0, 0x94, 0x71, 0x2b, 0x80, 0x40, 0x6c, 0x07, 0x5e, 0x0f, 0xe2, 0x91, 0xe2, 0xc9,
-	0x29, 0xca, 0x38, 0xbe, 0x0a, 0xa3, 0x9b, 0xe3, 0x18, 0x8d, 0x7b, 0x71, 0x35, 0x1a, 0x8d, 0x3f,
-	0xa7, 0xae, 0x46, 0xe3, 0x41, 0x22, 0x1a, 0x57, 0x9f, 0xae, 0xfc, 0x69, 0xb8, 0xbb, 0x35, 0x8e,
-	0xbb, 0xc4, 0xd6, 0x27, 0x84, 0xa4, 0x6f, 0x42, 0xc1, 0x5b, 0x7a, 0x76, 0x14, 0x95, 0x12, 0x51,
-	0x54, 0x52, 0x28, 0xfa, 0x7e, 0x0c, 0x44, 0xab, 0x31, 0x10, 0x25, 0x9a, 0xe4, 0xc1, 0x62, 0x2d,
-	0x0e, 0x8b, 0x44, 0x6e, 0xbf, 0xd0, 0x5f, 0x81, 0x92, 0x7c, 0x87, 0x60, 0xc2, 0x86, 0xd4, 0x8e,
-	0x5c, 0x2f, 0x5a, 0xf4, 0x22, 0x7e, 0x5f, 0x83, 0x79, 0xd5, 0x95, 0xa9, 0x34, 0xa9, 0x97, 0x45,
-	0x52, 0xef, 0xb9, 0x18, 0xe9, 0xe0, 0xe5, 0xc9, 0x11, 0xee, 0xc1, 0xe7, 0x21, 0x2b, 0xda, 0x64,
-	0xaf, 0x31, 0x13, 0x04, 0x9f, 0x15, 0xd5, 0xa4, 0xda, 0x32, 0x49, 0xf0, 0x67, 0xb2, 0x7f, 0xcf,
-	0xcb, 0x2b, 0xcf, 0xa7, 0x8d, 0x47, 0x9a, 0xec, 0x0e, 0x42, 0x46, 0x71, 0x3f, 0x18, 0x41, 0xeb,
-	0x80, 0x68, 0x9b, 0x47, 0x91, 0x74, 0xdf, 0x22, 0x36, 0x71, 0x44, 0x30, 0x85, 0x8d, 0x69, 0x9c,
-	0xb0, 0xc2, 0x77, 0x71, 0xc8, 0xb0, 0x6f, 0x75, 0x4c, 0x26, 0xec, 0xcd, 0x62, 0x9f, 0x46, 0x2b,
-	0x30, 0x3b, 0x12, 0xef, 0xe0, 0x2e, 0xf6, 0x58, 0xd2, 0x82, 0x25, 0x3e, 0x8d, 0xbe, 0x00, 0x25,
-	0x87, 0x98, 0xdd, 0x33, 0x9f, 0x4f, 0x5e, 0xdd, 0xd1, 0x49, 0x74, 0x13, 0xe6, 0xfc, 0x0a, 0xf2,
-	0x39, 0xb3, 0x82, 0x73, 0x7c, 0xc1, 0xf8, 0xb5, 0x06, 0xb0, 0x47, 0x7b, 0x2f, 0x41, 0xb8, 0xd1,
-	0x2a, 0xe4, 0xe9, 0x50, 0x82, 0x2d, 0x27, 0xc0, 0xe6, 0x7d, 0x65, 0xd9, 0xa3, 0xbd, 0x03, 0xb9,
-	0x80, 0x3d, 0x0e, 0xa3, 0x21, 0xcc, 0x56, 0xd3, 0x68, 0x01, 0x74, 0xd7, 0xb4, 0xfa, 0x7b, 0x96,
-	0x4d, 0x98, 0x4a, 0x43, 0x30, 0xc1, 0xf1, 0x71, 0x8f, 0xf6, 0xfb, 0xf4, 0x3d, 0x61, 0x7c, 0x01,
-	0x2b, 0xca, 0x78, 0x15, 0xd2, 0x7b, 0xb4, 0x97, 0xe8, 0x73, 0xa8, 0x52, 0x53, 0xd1, 0x4a, 0xbd,
-	0x05, 0x45, 0x11, 0x2f, 0x55, 0x09, 0x06, 0x7f, 0x87, 0xb3, 0x51, 0xdf, 0xfb, 0x6c, 0x0e, 0x81,
-	0xcd, 0x58, 0xad, 0x18, 0x27, 0x50, 0x8a, 0x94, 0x51, 0xe2, 0x8e, 0x08, 0x32, 0xc7, 0x78, 0x57,
-	0x3e, 0xd4, 0x74, 0x2c, 0xc6, 0xdc, 0xad, 0xe0, 0xc8, 0x90, 0x45, 0x11, 0x4c, 0xf0, 0x08, 0xbb,
-	0xd4, 0x35, 0xfb, 0xaa, 0x0c, 0x24, 0x61, 0xcc, 0x03, 0xf2, 0x5f, 0x86, 0xc4, 0x7b, 0xa0, 0x1b,
-	0x0d, 0xb8, 0x16, 0x99, 0x55, 0xd6, 0x87, 0x5f, 0xab, 0xda, 0xff, 0x78, 0xad, 0xd6, 0xdf, 0x4f,
-	0x43, 0x5e, 0x9d, 0xbe, 0x68, 0x13, 0x72, 0xca, 0x97, 0x69, 0x25, 0x20, 0x1e, 0x2b, 0xd5, 0x6b,
-	0x09, 0xf7, 0xa4, 0x31, 0xfb, 0xc3, 0xdf, 0xff, 0xed, 0xa7, 0x29, 0x1d, 0xe5, 0x6b, 0xcc, 0xeb,
-	0x1c, 0x73, 0xf2, 0x60, 0x40, 0xf1, 0x07, 0xa9, 0xb2, 0xb7, 0x3a, 0x1f, 0xf9, 0xc0, 0xa6, 0xce,
-	0x0f, 0x63, 0x5e, 0x28, 0x9a, 0x31, 0xf4, 0x9a, 0x67, 0xd4, 0x86, 0xf6, 0x25, 0xf4, 0x23, 0x2d,
-	0x1e, 0xdf, 0xcf, 0x84, 0xac, 0x88, 0x9f, 0x28, 0xd5, 0x85, 0xe4, 0x45, 0x19, 0x11, 0xe3, 0x35,
-	0xb1, 0xc5, 0x2d, 0x54, 0xab, 0x9d, 0x07, 0x25, 0x7e, 0x51, 0x3b, 0x17, 0x45, 0x7d, 0x51, 0x3b,
-	0x17, 0x65, 0x7c, 0x51, 0x3b, 0xf7, 0xaa, 0xf6, 0xa2, 0x76, 0xce, 0xd3, 0x77, 0x81, 0x46, 0x50,
-	0x54, 0x1a, 0xf7, 0x68, 0x8f, 0xa1, 0x50, 0xed, 0x7a, 0x1b, 0x87, 0x4a, 0xc3, 0x68, 0x8a, 0x6d,
-	0xbe, 0x66, 0xdc, 0xae, 0xf5, 0x69, 0x8f, 0x7d, 0xcc, 0xbd, 0x36, 0x3c, 0x14, 0x7c, 0x59, 0x6b,
-	0x2c, 0x7c, 0xf4, 0x68, 0x51, 0xfb, 0xe7, 0xa3, 0x45, 0xed, 0xe1, 0xe3, 0x45, 0xed, 0xc3, 0xc7,
-	0x8b, 0xda, 0x47, 0x8f, 0x17, 0xb5, 0xbf, 0x3c, 0x5e, 0xd4, 0x3e, 0xf8, 0xeb, 0xe2, 0x54, 0x3b,
-	0x27, 0xde, 0xa4, 0xaf, 0xfe, 0x37, 0x00, 0x00, 0xff, 0xff, 0x93, 0xb3, 0x54, 0x2c, 0xb0, 0x1a,
-	0x00, 0x00,
+func init() { proto.RegisterFile("types/types.proto", fileDescriptor_types_401285c03e2a8771) }
+
+var fileDescriptor_types_401285c03e2a8771 = []byte{
+	// 2257 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x19, 0x4d, 0x6f, 0x1c, 0x49,
+	0xd5, 0x3d, 0xdf, 0xfd, 0xc6, 0x63, 0x8f, 0x2b, 0x76, 0x76, 0x18, 0x8c, 0x6d, 0x1a, 0x24, 0xbc,
+	0x9b, 0xd8, 0x43, 0x66, 0x03, 0xbb, 0xc9, 0x46, 0x02, 0xcf, 0xb8, 0x37, 0x1a, 0xad, 0x63, 0x9b,
+	0xf2, 0x38, 0xcb, 0xb2, 0x48, 0x51, 0x7b, 0xba, 0x32, 0xdb, 0x78, 0xa6, 0xab, 0xd3, 0xdd, 0xe3,
+	0x8d, 0x15, 0xf9, 0x00, 0x5c, 0xe0, 0xba, 0x5c, 0xf6, 0x02, 0x82, 0x03, 0x47, 0x8e, 0xfc, 0x00,
+	0x2e, 0x28, 0xe2, 0xb4, 0x12, 0x12, 0xac, 0x10, 0xb2, 0x48, 0xe0, 0x80, 0xc2, 0x05, 0x71, 0xe1,
+	0xc2, 0x01, 0xd5, 0x47, 0x7f, 0x4c, 0xbb, 0x1d, 0xb2, 0xd9, 0x59, 0x94, 0x8b, 0xdd, 0xef, 0xd5,
+	0x7b, 0xaf, 0xde, 0x67, 0xd5, 0x7b, 0x35, 0x30, 0xe7, 0x1f, 0x3b, 0xc4, 0x6b, 0xf0, 0xbf, 0xeb,
+	0x8e, 0x4b, 0x7d, 0x8a, 0xf2, 0x1c, 0xa8, 0xaf, 0xf5, 0x2d, 0xff, 0xbd, 0xd1, 0xc1, 0x7a, 0x8f,
+	0x0e, 0x1b, 0x7d, 0xda, 0xa7, 0x0d, 0xbe, 0x7a, 0x30, 0xba, 0xcb, 0x21, 0x0e, 0xf0, 0x2f, 0xc1,
+	0x55, 0x5f, 0xec, 0x53, 0xda, 0x1f, 0x90, 0x86, 0xe1, 0x58, 0x0d, 0xc3, 0xb6, 0xa9, 0x6f, 0xf8,
+	0x16, 0xb5, 0xa5, 0x4c, 0xed, 0x5d, 0x98, 0xc6, 0xc4, 0xa3, 0x23, 0xb7, 0x47, 0xf6, 0x6d, 0xcb,
+	0x47, 0x9f, 0x83, 0x6c, 0x7b, 0x77, 0xbf, 0xa6, 0xac, 0x28, 0xab, 0x95, 0x56, 0xf1, 0xc9, 0xe9,
+	0x72, 0xb6, 0xe7, 0x8c, 0x30, 0xc3, 0xa1, 0x8b, 0x50, 0x18, 0x92, 0x21, 0x75, 0x8f, 0x6b, 0x99,
+	0x15, 0x65, 0x35, 0x87, 0x25, 0x84, 0x10, 0xe4, 0x4c, 0xcb, 0x3b, 0xac, 0x65, 0x39, 0x96, 0x7f,
+	0x5f, 0xcf, 0xfd, 0xfd, 0x17, 0xcb, 0x8a, 0xe6, 0x40, 0x25, 0x10, 0x7e, 0xd3, 0xa5, 0x23, 0x07,
+	0xad, 0x41, 0x6e, 0x64, 0x5b, 0x3e, 0x17, 0x5f, 0x6e, 0x5e, 0x58, 0x17, 0xd6, 0xc5, 0x15, 0x68,
+	0xe5, 0x1e, 0x9e, 0x2e, 0x4f, 0x61, 0x4e, 0x86, 0xe6, 0x21, 0xdf, 0xa3, 0x23, 0xdb, 0xe7, 0x1b,
+	0x56, 0xb0, 0x00, 0x18, 0xd6, 0x71, 0xad, 0x1e, 0x91, 0x1b, 0x0a, 0x40, 0xee, 0xd8, 0x86, 0xb9,
+	0x5d, 0x97, 0x1e, 0x59, 0x26, 0x71, 0x37, 0x7c, 0xdf, 0xb5, 0x0e, 0x46, 0x3e, 0x61, 0x0a, 0xda,
+	0xc6, 0x90, 0xf0, 0x5d, 0x55, 0xcc, 0xbf, 0x99, 0x90, 0x23, 0x63, 0x30, 0x22, 0x5c, 0xb4, 0x8a,
+	0x05, 0x20, 0x85, 0xfc, 0x54, 0x01, 0x95, 0xeb, 0xbb, 0xe7, 0x90, 0x5e, 0x2a, 0x77, 0x0b, 0xa6,
+	0x5d, 0x72, 0x6f, 0x64, 0xb9, 0x64, 0x48, 0x6c, 0xdf, 0xab, 0x65, 0x56, 0xb2, 0xab, 0xe5, 0x66,
+	0x4d, 0xda, 0x73, 0x46, 0x03, 0x69, 0xd4, 0x18, 0x0f, 0x7a, 0x1d, 0x54, 0x57, 0x1a, 0xee, 0xd5,
+	0xb2, 0x5c, 0xc0, 0x7c, 0xc2, 0x21, 0x5c, 0x09, 0xc9, 0x1c, 0x11, 0x6b, 0x3f, 0x56, 0x60, 0x6e,
+	0x93, 0x38, 0x03, 0x7a, 0xcc, 0x24, 0x71, 0xa2, 0xce, 0x26, 0xfa, 0x2e, 0x80, 0x19, 0x22, 0xb9,
+	0xb6, 0xd3, 0xad, 0x1b, 0x8c, 0xf5, 0x4f, 0xa7, 0xcb, 0x57, 0x63, 0x29, 0xb3, 0x61, 0x1f, 0xba,
+	0x6b, 0x36, 0xf1, 0xdf, 0xa7, 0xee, 0x61, 0xc3, 0xec, 0xf5, 0xec, 0x35, 0xd3, 0x20, 0x43, 0x6a,
+	0x8b, 0x54, 0x6b, 0x1c, 0x18, 0x1e, 0x59, 0x6f, 0x1d, 0xfb, 0xc4, 0xc3, 0x31, 0x79, 0xa8, 0x0a,
+	0x59, 0x8f, 0xdc, 0x93, 0x91, 0x67, 0x9f, 0xd7, 0x73, 0x1f, 0xfe, 0x7c, 0x79, 0x4a, 0xfb, 0x77,
+	0x06, 0x66, 0x13, 0xba, 0xa0, 0x26, 0x64, 0x2c, 0x53, 0xc6, 0x38, 0xf0, 0xc9, 0x19, 0x7d, 0x5b,
+	0x25, 0xa6, 0xdb, 0x47, 0xa7, 0xcb, 0x0a, 0xce, 0x58, 0x66, 0xe8, 0xe5, 0x4c, 0xcc, 0xcb, 0x75,
+	0x28, 0x51, 0xd7, 0x24, 0x6e, 0xb7, 0xbb, 0xc5, 0x63, 0x9d, 0xc5, 0x21, 0x8c, 0x36, 0x20, 0xef,
+	0xf9, 0x86, 0x4f, 0x6a, 0xb9, 0x15, 0x65, 0x75, 0xa6, 0x79, 0x29, 0x7d, 0x9b, 0x24, 0xbc, 0xc7,
+	0x58, 0xb0, 0xe0, 0x3c, 0x13, 0xc4, 0xfc, 0xa7, 0x0d, 0x62, 0xe1, 0x93, 0x04, 0xf1, 0x1a, 0xcc,
+	0xa7, 0x29, 0x87, 0x4a, 0x90, 0xdb, 0xd9, 0xd5, 0xb7, 0xab, 0x53, 0xa8, 0x0c, 0xc5, 0x1d, 0xbc,
+	0xa9, 0x63, 0x7d, 0xb3, 0xaa, 0x20, 0x80, 0x42, 0x7b, 0x6b, 0x67, 0x4f, 0xdf, 0xac, 0x66, 0x65,
+	0x96, 0x7e, 0x13, 0xaa, 0x09, 0x01, 0x1e, 0xba, 0x0c, 0x79, 0xcb, 0x27, 0x43, 0xaf, 0xa6, 0x70,
+	0x55, 0x2e, 0xa6, 0x7b, 0x05, 0x0b, 0x22, 0xed, 0x3f, 0x19, 0x80, 0x68, 0x09, 0xdd, 0x86, 0xa2,
+	0x61, 0x9a, 0x2e, 0xf1, 0xbc, 0x89, 0x64, 0x4f, 0x20, 0x0c, 0x75, 0xa1, 0xe0, 0x13, 0xdb, 0x90,
+	0x65, 0xfc, 0x69, 0xc5, 0x4a, 0x59, 0xe8, 0xb5, 0x20, 0x01, 0xb2, 0x3c, 0x01, 0xbe, 0x78, 0xc6,
+	0xd4, 0xd8, 0xe7, 0x58, 0xd8, 0x6f, 0x43, 0xf1, 0x88, 0xb8, 0x9e, 0x45, 0xed, 0x5a, 0x6e, 0x02,
+	0xfa, 0x04, 0xc2, 0xb4, 0x97, 0x61, 0x36, 0xb1, 0x23, 0x0b, 0xda, 0x46, 0xbb, 0xdb, 0xb9, 0xad,
+	0x57, 0xa7, 0x62, 0x01, 0xcc, 0xc8, 0x00, 0xde, 0x80, 0x72, 0xc4, 0xe0, 0xa1, 0xb5, 0xf1, 0xd8,
+	0xcd, 0x9d, 0x31, 0x48, 0xe6, 0x90, 0x0c, 0xde, 0xcf, 0x32, 0x80, 0xba, 0xf7, 0xdb, 0x2e, 0x31,
+	0x7c, 0x12, 0x0b, 0x62, 0xe4, 0x6c, 0x65, 0x82, 0xce, 0x9e, 0x87, 0xbc, 0x4d, 0xed, 0x1e, 0x91,
+	0xf5, 0x2f, 0x80, 0xa7, 0xd6, 0xe7, 0x67, 0xe4, 0x65, 0xb4, 0x0a, 0x85, 0x3e, 0xcf, 0x75, 0x59,
+	0xae, 0x55, 0xe9, 0xa6, 0xf0, 0xbc, 0xc6, 0x72, 0x5d, 0xfb, 0x9d, 0xc2, 0x1c, 0xb4, 0xef, 0x98,
+	0xe3, 0x0e, 0xfa, 0x6c, 0x8f, 0xc9, 0x98, 0xd9, 0x99, 0x49, 0x26, 0xd7, 0x3f, 0x14, 0x98, 0xeb,
+	0xde, 0x6f, 0x0f, 0xa8, 0xf7, 0xff, 0xb3, 0xe5, 0x0d, 0x28, 0xb8, 0xc4, 0xf0, 0xa4, 0x29, 0x33,
+	0xcd, 0x2f, 0x49, 0x57, 0x9f, 0xd1, 0x63, 0x1d, 0x73, 0xb2, 0x36, 0x35, 0x09, 0x96, 0x2c, 0xda,
+	0x1b, 0x00, 0x11, 0x16, 0xa9, 0x90, 0xdf, 0xdf, 0xde, 0xd3, 0xbb, 0xd5, 0x29, 0x54, 0x85, 0xe9,
+	0xae, 0xbe, 0xbd, 0xb1, 0xdd, 0xbd, 0xc3, 0xcb, 0xa1, 0xaa, 0x30, 0x4c, 0x67, 0x7b, 0x6f, 0xff,
+	0xcd, 0x37, 0x3b, 0xed, 0x8e, 0xbe, 0xdd, 0xad, 0x66, 0xb4, 0x5f, 0xe6, 0x00, 0xba, 0xf7, 0x75,
+	0xfb, 0x88, 0x0c, 0xa8, 0x43, 0xd0, 0x2b, 0x90, 0x63, 0x3b, 0x73, 0x03, 0x67, 0xc2, 0x43, 0x2d,
+	0x22, 0x58, 0xef, 0x1e, 0x3b, 0x04, 0x73, 0x1a, 0x54, 0x1b, 0x0f, 0x40, 0x25, 0xca, 0x9c, 0x25,
+	0x00, 0xd3, 0xf0, 0x8d, 0x36, 0xb1, 0x7d, 0xe2, 0xf2, 0x7c, 0x55, 0x71, 0x0c, 0x83, 0x16, 0x41,
+	0x65, 0xb7, 0x8e, 0xe7, 0x18, 0x3d, 0x71, 0xab, 0xa8, 0x38, 0x42, 0xa0, 0x15, 0x28, 0x3b, 0xc4,
+	0xb5, 0xa8, 0xb9, 0xe7, 0x1b, 0xae, 0x5f, 0xcb, 0xf3, 0x74, 0x8f, 0xa3, 0x18, 0xbf, 0x00, 0x75,
+	0xdb, 0xac, 0x15, 0xf8, 0x7a, 0x84, 0x08, 0xee, 0xcf, 0x62, 0x78, 0x7f, 0x32, 0x4d, 0x1d, 0xe3,
+	0x78, 0x40, 0x0d, 0xb3, 0x56, 0x62, 0x91, 0xc3, 0x01, 0xc8, 0x6a, 0xd8, 0x19, 0x1d, 0xbc, 0x45,
+	0x8e, 0x6b, 0xea, 0x24, 0x6a, 0x58, 0xc8, 0x42, 0xdf, 0x01, 0xd5, 0xb3, 0xfa, 0xb6, 0xe1, 0x8f,
+	0x5c, 0x52, 0x83, 0x09, 0x08, 0x8e, 0xc4, 0x69, 0x23, 0xc8, 0xb1, 0x18, 0xb0, 0x2b, 0x6b, 0x7f,
+	0xfb, 0xad, 0xed, 0x9d, 0xb7, 0xd9, 0xfd, 0x35, 0x0d, 0xa5, 0x5b, 0x7a, 0x57, 0xc7, 0x9d, 0xed,
+	0x9b, 0x55, 0x05, 0x2d, 0xc0, 0x5c, 0x1b, 0xeb, 0x1b, 0x5d, 0xfd, 0xce, 0xa6, 0xbe, 0xbb, 0xb5,
+	0xf3, 0xce, 0x2d, 0x1e, 0x6a, 0x34, 0x0f, 0x55, 0x9e, 0x07, 0x71, 0x6c, 0x16, 0xcd, 0x00, 0x48,
+	0xe2, 0x56, 0x67, 0xb3, 0x9a, 0x63, 0xcc, 0x58, 0xbf, 0xd9, 0xd9, 0xeb, 0xea, 0xf8, 0xce, 0x2e,
+	0xde, 0xb9, 0xdd, 0xd9, 0xd4, 0x71, 0x35, 0xaf, 0x3d, 0xcc, 0xc0, 0xec, 0x2d, 0xc3, 0xb6, 0xee,
+	0x12, 0xcf, 0xc7, 0xe4, 0xde, 0x88, 0x78, 0x3e, 0xda, 0x86, 0xec, 0x21, 0x39, 0x9e, 0x48, 0x31,
+	0x64, 0x0f, 0x93, 0x6e, 0xcb, 0x4c, 0xd4, 0x6d, 0x89, 0xfa, 0xcd, 0x4e, 0xb8, 0x7e, 0x2f, 0x41,
+	0x69, 0x28, 0x9d, 0xc3, 0xf3, 0xb9, 0xdc, 0x9c, 0x95, 0xa5, 0x13, 0xfa, 0x2c, 0x24, 0xd0, 0x5e,
+	0x87, 0x52, 0x80, 0x45, 0x97, 0xc3, 0x33, 0x56, 0x19, 0xeb, 0x68, 0x02, 0x02, 0xd1, 0x44, 0x04,
+	0xe7, 0xec, 0xdb, 0x50, 0x19, 0x5b, 0x48, 0x6d, 0x98, 0x9b, 0x50, 0xf2, 0x88, 0x7b, 0x64, 0xf5,
+	0x48, 0xd0, 0x2c, 0x5f, 0x4c, 0x08, 0xdd, 0x13, 0xcb, 0x38, 0xa4, 0xd3, 0xfe, 0xa0, 0xc0, 0x6c,
+	0x62, 0xf5, 0xbc, 0x56, 0xde, 0x1a, 0x1a, 0xfd, 0xb0, 0x95, 0xe7, 0x00, 0xa3, 0x34, 0xdc, 0xbe,
+	0xe8, 0xac, 0x55, 0xcc, 0xbf, 0x59, 0x11, 0x12, 0xfb, 0xa8, 0x96, 0xe3, 0x28, 0xf6, 0x89, 0xbe,
+	0x22, 0x07, 0x92, 0xfc, 0xb9, 0x03, 0x49, 0x72, 0x14, 0x29, 0xc4, 0x47, 0x91, 0xab, 0x50, 0x20,
+	0xf7, 0x1d, 0xea, 0x91, 0x5a, 0x91, 0x1b, 0xb5, 0x98, 0x6e, 0x94, 0xce, 0x69, 0xb0, 0xa4, 0xd5,
+	0x7e, 0xa5, 0xc0, 0x42, 0x2a, 0x05, 0x53, 0xda, 0xa1, 0xae, 0x38, 0xca, 0x2b, 0x98, 0x7f, 0x23,
+	0x0d, 0xa6, 0xc9, 0x7d, 0x9f, 0xb8, 0xb6, 0x31, 0xd8, 0x65, 0x6b, 0xe2, 0x58, 0x1b, 0xc3, 0x89,
+	0x91, 0x88, 0xfa, 0x54, 0x1e, 0x6b, 0x02, 0x60, 0x27, 0x8c, 0x74, 0xa6, 0x3c, 0xcf, 0x02, 0x90,
+	0x8d, 0x72, 0xfd, 0x01, 0x3d, 0x30, 0x06, 0xdc, 0xf0, 0x12, 0x96, 0x10, 0x93, 0xf3, 0x1e, 0xf5,
+	0x7c, 0xd1, 0xca, 0xaa, 0x58, 0x00, 0x5a, 0x11, 0xf2, 0xfa, 0xd0, 0xf1, 0x8f, 0xb5, 0x1d, 0x28,
+	0xde, 0x96, 0xa7, 0x69, 0xec, 0x9c, 0x15, 0xb1, 0x08, 0x40, 0x26, 0xbb, 0x47, 0x87, 0x43, 0xcb,
+	0x97, 0xf1, 0x90, 0x10, 0x1f, 0x13, 0x83, 0x7e, 0x4d, 0xc5, 0xfc, 0x5b, 0xfb, 0x51, 0x06, 0xa6,
+	0x99, 0x07, 0x88, 0xcb, 0x1a, 0xa6, 0x91, 0x87, 0xbe, 0x0d, 0x25, 0x47, 0x36, 0xde, 0x13, 0x29,
+	0xe1, 0x50, 0x1a, 0xfa, 0xda, 0xf8, 0xc5, 0x50, 0x6e, 0xce, 0xc8, 0x58, 0x49, 0x8b, 0x5a, 0xe5,
+	0x27, 0xa7, 0xcb, 0x01, 0x49, 0x64, 0xcd, 0x35, 0x28, 0x78, 0x5c, 0x35, 0xae, 0x77, 0xb9, 0xb9,
+	0x90, 0x18, 0x0f, 0x84, 0xde, 0x2d, 0x78, 0x72, 0xba, 0x2c, 0x09, 0xb1, 0xfc, 0xcf, 0x0c, 0xee,
+	0x51, 0x53, 0xf8, 0x3e, 0x8f, 0xf9, 0x37, 0x73, 0xdb, 0x90, 0x78, 0x1e, 0xcb, 0xd6, 0xbc, 0x70,
+	0x9b, 0x04, 0xb5, 0x7f, 0x95, 0x60, 0x21, 0xee, 0x8a, 0x5d, 0xc3, 0xf5, 0x88, 0x71, 0x30, 0x20,
+	0x2f, 0x9e, 0x4f, 0x76, 0x12, 0x3e, 0x09, 0x86, 0xaf, 0x54, 0xf5, 0x27, 0xee, 0xa9, 0xfa, 0x1f,
+	0x15, 0x98, 0x19, 0x17, 0x8a, 0xb6, 0xa0, 0xd8, 0x1b, 0x8c, 0x3c, 0x5f, 0x7a, 0xa8, 0xdc, 0x6c,
+	0x3e, 0x93, 0x4a, 0x6d, 0xc1, 0x23, 0x96, 0x71, 0x20, 0x02, 0x5d, 0x8b, 0x1d, 0x9c, 0xc2, 0x2f,
+	0x5f, 0x48, 0x44, 0x3d, 0xac, 0x5e, 0xc1, 0x19, 0x92, 0xa3, 0x1b, 0xa0, 0x1e, 0x58, 0x26, 0xb1,
+	0xfb, 0x96, 0x4d, 0xa4, 0x77, 0x96, 0x12, 0xbc, 0xad, 0x60, 0x5d, 0x32, 0x47, 0x0c, 0xf5, 0x43,
+	0x58, 0x48, 0x55, 0x0d, 0x61, 0x50, 0x2d, 0xfb, 0x88, 0xd8, 0x3e, 0x7b, 0x7d, 0x11, 0x16, 0x5e,
+	0x7d, 0x26, 0x0b, 0x3b, 0x01, 0x57, 0xb0, 0x59, 0x28, 0xa6, 0xfe, 0xfd, 0x1c, 0xbc, 0x74, 0x0e,
+	0x19, 0x22, 0x6c, 0x34, 0x66, 0x87, 0x85, 0x78, 0x2b, 0x92, 0x5b, 0x6e, 0x3c, 0xcf, 0x96, 0xeb,
+	0x38, 0x26, 0x08, 0x8f, 0x89, 0x45, 0x77, 0x40, 0x35, 0x8e, 0x0c, 0x6b, 0xc0, 0xf8, 0xe5, 0xb5,
+	0xf0, 0xdc, 0x7b, 0x44, 0x07, 0x76, 0x24, 0xb3, 0xbe, 0xff, 0xbc, 0xaf, 0x5b, 0x6a, 0xea, 0xeb,
+	0x96, 0x2a, 0x5e, 0xb7, 0xea, 0xbf, 0x55, 0xb8, 0xdc, 0xc8, 0x90, 0x77, 0xa0, 0x60, 0xf4, 0x7c,
+	0xeb, 0x88, 0xd4, 0x94, 0x49, 0x59, 0x21, 0x05, 0xa2, 0x77, 0xa1, 0xe8, 0x10, 0xdb, 0xb4, 0xec,
+	0xfe, 0xe4, 0x3c, 0x14, 0x48, 0xd4, 0x7e, 0x73, 0xb6, 0x94, 0xbe, 0x9e, 0x2c, 0xa5, 0xc5, 0x44,
+	0xfe, 0xbe, 0x60, 0x45, 0xa3, 0x5d, 0x87, 0x8b, 0xe9, 0x3b, 0xb0, 0x9e, 0x3d, 0x6a, 0x87, 0x3c,
+	0x79, 0xa9, 0xc6, 0x51, 0xda, 0x15, 0x78, 0xe9, 0x9c, 0x1d, 0x58, 0x3e, 0xf0, 0x61, 0x36, 0xe0,
+	0x93, 0x90, 0xb6, 0x7f, 0x5e, 0x8d, 0xde, 0x38, 0x5b, 0xa3, 0x49, 0x2b, 0xce, 0xaf, 0x46, 0xed,
+	0xcf, 0x99, 0xf3, 0xab, 0x71, 0x27, 0xb5, 0x1a, 0x2f, 0x3d, 0x5d, 0xf8, 0xd3, 0xea, 0xee, 0xca,
+	0xd9, 0xba, 0x4b, 0x6d, 0x7d, 0x62, 0x95, 0xf4, 0x2d, 0x28, 0x05, 0x4b, 0xcf, 0x5e, 0x45, 0x95,
+	0xd4, 0x2a, 0xaa, 0xc8, 0x2a, 0xfa, 0x5e, 0xa2, 0x88, 0x2e, 0x25, 0x8a, 0x28, 0x55, 0xa5, 0xa0,
+	0x2c, 0xd6, 0x92, 0x65, 0x91, 0x4a, 0x1d, 0x26, 0xfa, 0xcb, 0x50, 0x11, 0xf3, 0x2a, 0x26, 0x9e,
+	0x43, 0xed, 0xb1, 0xeb, 0x45, 0x19, 0xbf, 0x88, 0x3f, 0x50, 0x60, 0x5e, 0x76, 0x65, 0x32, 0x4c,
+	0x72, 0xb2, 0x48, 0xeb, 0x3d, 0x97, 0xc6, 0x3a, 0xf8, 0x8c, 0x1c, 0x2a, 0x43, 0x0c, 0x6b, 0xa8,
+	0x78, 0x9b, 0x1c, 0x34, 0x66, 0x1c, 0x60, 0x58, 0x9e, 0x4d, 0xb2, 0x2d, 0x13, 0x00, 0x7b, 0x4e,
+	0x09, 0xef, 0x79, 0x71, 0xe5, 0x85, 0xb0, 0xf6, 0x48, 0x81, 0x85, 0x84, 0x52, 0xcc, 0x0e, 0x8f,
+	0xa0, 0x75, 0x40, 0xf4, 0x80, 0x79, 0x91, 0x98, 0x37, 0x89, 0x4d, 0x5c, 0xee, 0x4c, 0xae, 0x63,
+	0x16, 0xa7, 0xac, 0xb0, 0x5d, 0x5c, 0xe2, 0x0c, 0xac, 0x9e, 0xe1, 0x71, 0x7d, 0xf3, 0x38, 0x84,
+	0xd1, 0x2a, 0xcc, 0x8e, 0xf8, 0x7b, 0x89, 0x89, 0x03, 0x92, 0x2c, 0x27, 0x49, 0xa2, 0xd1, 0x97,
+	0xa1, 0xe2, 0x12, 0xc3, 0x3c, 0x0e, 0xe9, 0xc4, 0xd5, 0x3d, 0x8e, 0x44, 0x97, 0x61, 0x2e, 0xcc,
+	0xa0, 0x90, 0x32, 0xcf, 0x29, 0xcf, 0x2e, 0x68, 0xbf, 0x56, 0x00, 0xb6, 0x68, 0xff, 0x05, 0x70,
+	0x37, 0xba, 0x04, 0x45, 0xea, 0x88, 0x62, 0x2b, 0xac, 0x28, 0xb1, 0xd7, 0xb8, 0x2d, 0xda, 0xdf,
+	0x11, 0x0b, 0x38, 0xa0, 0xd0, 0x5a, 0x00, 0x11, 0x9a, 0x3d, 0x03, 0xf8, 0x86, 0x35, 0xd8, 0xb2,
+	0x6c, 0xe2, 0xc9, 0x30, 0x44, 0x08, 0x56, 0x1f, 0x77, 0xe9, 0x60, 0x40, 0xdf, 0xe7, 0xca, 0x97,
+	0xb0, 0x84, 0xb4, 0x57, 0x21, 0xbb, 0x45, 0xfb, 0xa9, 0x36, 0xc7, 0x32, 0x35, 0x33, 0x9e, 0xa9,
+	0x57, 0xa0, 0xcc, 0xfd, 0x25, 0x33, 0x41, 0x63, 0xef, 0x35, 0xde, 0x68, 0x10, 0xfc, 0xbc, 0x02,
+	0x91, 0xce, 0x58, 0xae, 0x68, 0x87, 0x50, 0x19, 0x4b, 0xa3, 0xd4, 0x1d, 0x11, 0xe4, 0xf6, 0x71,
+	0x47, 0x0c, 0x6a, 0x2a, 0xe6, 0xdf, 0xcc, 0xac, 0xe8, 0xc8, 0x10, 0x49, 0x11, 0x21, 0x98, 0x87,
+	0x7d, 0xea, 0x1b, 0x03, 0x99, 0x06, 0x02, 0xd0, 0xe6, 0x01, 0x85, 0x93, 0x21, 0x09, 0x06, 0x74,
+	0xad, 0x05, 0x17, 0xc6, 0xb0, 0x52, 0xfb, 0xf8, 0xb4, 0xaa, 0xfc, 0x8f, 0x69, 0xb5, 0xf9, 0x41,
+	0x16, 0x8a, 0xf2, 0xf4, 0x45, 0x37, 0xa0, 0x20, 0x6d, 0x99, 0x96, 0x0c, 0x7c, 0x58, 0xa9, 0x5f,
+	0x48, 0xb9, 0x27, 0xb5, 0xd9, 0x1f, 0xfc, 0xfe, 0x6f, 0x3f, 0xc9, 0xa8, 0xa8, 0xd8, 0xf0, 0x82,
+	0xce, 0xb1, 0x20, 0x0e, 0x06, 0x94, 0x1c, 0x48, 0xa5, 0xbe, 0xf5, 0xf9, 0xb1, 0x87, 0x58, 0xa1,
+	0x2f, 0xd1, 0xe6, 0xb9, 0xa0, 0x19, 0x4d, 0x6d, 0x04, 0x4a, 0x5d, 0x57, 0x5e, 0x41, 0x3f, 0x54,
+	0x92, 0xfe, 0xfd, 0x7c, 0x4c, 0x8b, 0xe4, 0x89, 0x52, 0x5f, 0x4c, 0x5f, 0x14, 0x1e, 0xd1, 0x5e,
+	0xe3, 0x5b, 0x5c, 0x41, 0x8d, 0xc6, 0x83, 0x28, 0xc5, 0x4f, 0x1a, 0x0f, 0x78, 0x52, 0x9f, 0x34,
+	0x1e, 0xf0, 0x34, 0x3e, 0x69, 0x3c, 0x08, 0xb2, 0xf6, 0xa4, 0xf1, 0x80, 0x85, 0xef, 0x04, 0x8d,
+	0xa0, 0x2c, 0x25, 0x6e, 0xd1, 0xbe, 0x87, 0x62, 0xb9, 0x1b, 0x6c, 0x1c, 0x4b, 0x0d, 0x4d, 0xe7,
+	0xdb, 0x7c, 0x43, 0xbb, 0xda, 0x18, 0xd0, 0xbe, 0xf7, 0x09, 0xf7, 0xba, 0x1e, 0x54, 0xc1, 0x57,
+	0x95, 0xd6, 0xe2, 0xc7, 0x8f, 0x96, 0x94, 0x7f, 0x3e, 0x5a, 0x52, 0x1e, 0x3e, 0x5e, 0x52, 0x3e,
+	0x7a, 0xbc, 0xa4, 0x7c, 0xfc, 0x78, 0x49, 0xf9, 0xcb, 0xe3, 0x25, 0xe5, 0xc3, 0xbf, 0x2e, 0x4d,
+	0x1d, 0x14, 0xf8, 0x4c, 0xfa, 0xea, 0x7f, 0x07, 0x00, 0x6b, 0x31, 0x36, 0x69, 0xd8, 0x1c, 0x00,
+	0x00,
 }
@@ -1,11 +1,54 @@
 package types
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/Ankr-network/dccn-daemon/types/unit"
 	corev1 "k8s.io/api/core/v1"
 )
 
-//NewManifestService is a easy way to create a manifest service
+// Protocols of ManifestServiceExpose, global HTTP exposes are served by the
+// ingress, global TCP and UDP ones by node ports or load balancers
+const (
+	ExposeHTTP = "HTTP"
+	ExposeTCP  = string(corev1.ProtocolTCP)
+	ExposeUDP  = string(corev1.ProtocolUDP)
+)
+
+// IsHTTP reports whether the expose is served by the ingress
+func (m *ManifestServiceExpose) IsHTTP() bool {
+	return m.Proto == "" || strings.EqualFold(m.Proto, ExposeHTTP)
+}
+
+// Protocol is the transport protocol of the expose
+func (m *ManifestServiceExpose) Protocol() corev1.Protocol {
+	if strings.EqualFold(m.Proto, ExposeUDP) {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
+// Fits reports whether a unit of the service fits in the unit
+func (m *ResourceUnit) Fits(unit *ResourceUnit) bool {
+	return m.CPU >= unit.CPU && m.Memory >= unit.Memory && m.Disk >= unit.Disk
+}
+
+// IsTaskService reports whether the manifest service belongs to the task,
+// the services are suffixed by the image index in the multiple deployments
+// mode
+func IsTaskService(task, service string) bool {
+	if service == task {
+		return true
+	}
+	if !strings.HasPrefix(service, task+"-") {
+		return false
+	}
+	_, err := strconv.Atoi(service[len(task)+1:])
+	return err == nil
+}
+
+// NewManifestService is a easy way to create a manifest service
 func NewManifestService(name, image string) *ManifestService {
 	return &ManifestService{
 		Name:  name,
@@ -21,7 +64,7 @@ func NewManifestService(name, image string) *ManifestService {
 		Expose: []*ManifestServiceExpose{{
 			Port:         80,
 			ExternalPort: 80,
-			Proto:        string(corev1.ProtocolTCP),
+			Proto:        ExposeHTTP,
 			Service:      name,
 			Global:       true,
 			Hosts:        []string{name},
@@ -29,7 +72,7 @@ func NewManifestService(name, image string) *ManifestService {
 	}
 }
 
-//NewJobManifestService is a easy way to create a manifest service for job
+// NewJobManifestService is a easy way to create a manifest service for job
 func NewJobManifestService(name, image string) *ManifestService {
 	return &ManifestService{
 		Name:  name,